@@ -0,0 +1,177 @@
+package callback
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxBodySize = 4 << 20
+
+var (
+	ErrInvalidSignature = errors.New("invalid callback signature")
+	ErrEventExpired     = errors.New("callback event is expired")
+	ErrBodyTooLarge     = errors.New("callback body is too large")
+)
+
+// HandlerFunc 回调事件处理函数，返回错误时以500应答失败，环信会按回调规则重试，错误信息不会写入响应
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler 发送后回调处理器
+// 校验环信回调的签名，解析回调事件并按事件类型分发至注册的处理函数，处理成功后应答 HTTP 200。
+type Handler struct {
+	secret   string
+	maxAge   time.Duration
+	mu       sync.RWMutex
+	handlers map[EventType]HandlerFunc
+	fallback HandlerFunc
+}
+
+// NewHandler 创建发送后回调处理器，secret 为在环信控制台配置回调规则时设置的密钥
+func NewHandler(secret string) *Handler {
+	return &Handler{secret: secret, handlers: make(map[EventType]HandlerFunc)}
+}
+
+// Handle 注册指定事件类型的处理函数
+func (h *Handler) Handle(eventType EventType, fn HandlerFunc) {
+	h.mu.Lock()
+	h.handlers[eventType] = fn
+	h.mu.Unlock()
+}
+
+// SetMaxAge 设置回调事件的最长有效期，用于限制重放
+// 签名仅覆盖 callId、secret 与 timestamp，不包含事件内容，设置后拒绝 timestamp 与当前时间相差超过 maxAge 的事件。
+// maxAge 需大于环信回调的重试时长，小于等于 0 时不校验，默认不校验。
+func (h *Handler) SetMaxAge(maxAge time.Duration) {
+	h.mu.Lock()
+	h.maxAge = maxAge
+	h.mu.Unlock()
+}
+
+// HandleDefault 注册未匹配任何事件类型时的处理函数，未注册时直接应答成功
+func (h *Handler) HandleDefault(fn HandlerFunc) {
+	h.mu.Lock()
+	h.fallback = fn
+	h.mu.Unlock()
+}
+
+// ServeHTTP 处理回调请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := h.Decode(limitBody(w, r))
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if fn := h.match(event.Type()); fn != nil {
+		if err = fn(r.Context(), event); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Decode 解析并校验回调事件
+func (h *Handler) Decode(r io.Reader) (*Event, error) {
+	h.mu.RLock()
+	maxAge := h.maxAge
+	h.mu.RUnlock()
+
+	return decodeEvent(r, h.secret, maxAge)
+}
+
+// Verify 校验回调事件的签名
+func (h *Handler) Verify(event *Event) bool {
+	return verify(event, h.secret)
+}
+
+// 匹配事件类型的处理函数
+func (h *Handler) match(eventType EventType) HandlerFunc {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if fn, ok := h.handlers[eventType]; ok {
+		return fn
+	}
+
+	return h.fallback
+}
+
+// 限制回调请求体的大小，多读取一个字节以判断请求体是否超过上限
+func limitBody(w http.ResponseWriter, r *http.Request) io.Reader {
+	return http.MaxBytesReader(w, r.Body, maxBodySize+1)
+}
+
+// 解析并校验回调事件，maxAge 小于等于 0 时不校验事件的有效期
+func decodeEvent(r io.Reader, secret string, maxAge time.Duration) (*Event, error) {
+	buf, err := io.ReadAll(io.LimitReader(r, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buf) > maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+
+	event := &Event{}
+	if err = json.Unmarshal(buf, event); err != nil {
+		return nil, err
+	}
+
+	if !verify(event, secret) {
+		return nil, ErrInvalidSignature
+	}
+
+	if maxAge > 0 && isExpired(event, time.Now(), maxAge) {
+		return nil, ErrEventExpired
+	}
+
+	return event, nil
+}
+
+// 判断回调事件的时间与当前时间是否相差超过 maxAge
+func isExpired(event *Event, now time.Time, maxAge time.Duration) bool {
+	age := now.Sub(time.Unix(0, event.Timestamp*int64(time.Millisecond)))
+	if age < 0 {
+		age = -age
+	}
+
+	return age > maxAge
+}
+
+// 校验回调事件的签名，签名格式为 MD5(callId+secret+timestamp)
+func verify(event *Event, secret string) bool {
+	sum := md5.Sum([]byte(event.CallID + secret + strconv.FormatInt(event.Timestamp, 10)))
+	expected := hex.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(event.Security))) == 1
+}
+
+// 应答解析回调事件失败
+func writeDecodeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrEventExpired):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrBodyTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	}
+	http.Error(w, err.Error(), status)
+}
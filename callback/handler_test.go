@@ -0,0 +1,124 @@
+package callback_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/dobyte/easemob-im-server-sdk/callback"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSecret    = "secret"
+	testCallID    = "easemob-demo#test_1"
+	testTimestamp = int64(1600060847294)
+)
+
+// 构造带有正确签名的消息回调请求体
+func newMessageEvent(text string) string {
+	return newMessageEventAt(text, testTimestamp)
+}
+
+// 构造指定时间发送的带有正确签名的消息回调请求体
+func newMessageEventAt(text string, timestamp int64) string {
+	sum := md5.Sum([]byte(fmt.Sprintf("%s%s%d", testCallID, testSecret, timestamp)))
+
+	return fmt.Sprintf(`{"callId":"%s","eventType":"chat","timestamp":%d,"chat_type":"chat","from":"test1","to":"test2","msg_id":"1","payload":{"bodies":[{"type":"txt","msg":"%s"}],"ext":{}},"security":"%s"}`,
+		testCallID, timestamp, text, hex.EncodeToString(sum[:]))
+}
+
+func TestHandler(t *testing.T) {
+	var received []string
+
+	handler := callback.NewHandler(testSecret)
+	handler.Handle(callback.EventMessage, func(ctx context.Context, event *callback.Event) error {
+		payload, err := event.Message()
+		if err != nil {
+			return err
+		}
+
+		for _, body := range payload.Bodies {
+			received = append(received, fmt.Sprintf("%+v", body))
+		}
+
+		return nil
+	})
+
+	body := newMessageEvent("hello")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	if len(received) != 1 || received[0] != "&{Msg:hello}" {
+		t.Fatalf("unexpected bodies %v", received)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(strings.Replace(body, `"security":"`, `"security":"0`, 1))))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
+
+func TestHandler_HandlerError(t *testing.T) {
+	handler := callback.NewHandler(testSecret)
+	handler.Handle(callback.EventMessage, func(ctx context.Context, event *callback.Event) error {
+		return errors.New("dial tcp 10.0.0.1:3306: connection refused")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(newMessageEvent("hello"))))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	if strings.Contains(w.Body.String(), "10.0.0.1") {
+		t.Fatalf("expected the handler error not to be exposed, got %q", w.Body.String())
+	}
+}
+
+func TestHandler_BodyTooLarge(t *testing.T) {
+	handler := callback.NewHandler(testSecret)
+
+	body := newMessageEvent(strings.Repeat("a", 4<<20))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	if _, err := handler.Decode(strings.NewReader(body)); !errors.Is(err, callback.ErrBodyTooLarge) {
+		t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+	}
+}
+
+func TestHandler_MaxAge(t *testing.T) {
+	handler := callback.NewHandler(testSecret)
+
+	if _, err := handler.Decode(strings.NewReader(newMessageEvent("hello"))); err != nil {
+		t.Fatalf("expected old events to be accepted without max age, got %v", err)
+	}
+
+	handler.SetMaxAge(time.Minute)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(newMessageEvent("hello"))))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatal(w.Code, w.Body.String())
+	}
+
+	body := newMessageEventAt("hello", time.Now().UnixNano()/int64(time.Millisecond))
+	if _, err := handler.Decode(strings.NewReader(body)); err != nil {
+		t.Fatalf("expected a fresh event to be accepted, got %v", err)
+	}
+}
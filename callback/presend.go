@@ -0,0 +1,176 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"net/http"
+	"time"
+)
+
+const defaultPreSendTimeout = time.Second
+
+// Decision 发送前回调的处理结果
+type Decision struct {
+	valid   bool
+	code    string
+	message *message.Message
+}
+
+// Pass 放行消息
+func Pass() Decision {
+	return Decision{valid: true}
+}
+
+// Reject 拒绝下发消息，code 为返回给发送方的自定义错误码
+func Reject(code string) Decision {
+	return Decision{valid: false, code: code}
+}
+
+// Modify 使用修改后的消息替换原消息下发
+func Modify(msg *message.Message) Decision {
+	return Decision{valid: true, message: msg}
+}
+
+// PreSendFunc 发送前回调的处理函数，需在上下文结束前返回处理结果
+type PreSendFunc func(ctx context.Context, msg *message.Message) Decision
+
+// PreSendOptions 发送前回调处理器配置
+type PreSendOptions struct {
+	Secret   string        // 在环信控制台配置回调规则时设置的密钥。
+	BaseUrl  string        // 修改图片、语音、视频及文件消息时使用的文件地址前缀，格式为 https://{host}/{org_name}/{app_name}。
+	Timeout  time.Duration // 处理函数的超时时间，需小于控制台配置的回调超时时间，默认为 1s。
+	Fallback *Decision     // 处理超时、处理函数异常或消息无法解析时使用的处理结果，默认为放行。
+	MaxAge   time.Duration // 回调事件的最长有效期，拒绝 timestamp 与当前时间相差超过该值的事件以限制重放，小于等于 0 时不校验。
+}
+
+// PreSendHandler 发送前回调处理器
+// 校验签名并将回调中的消息解析为 message.Message，调用处理函数后以环信要求的格式应答放行、拒绝或修改消息。
+type PreSendHandler struct {
+	opts PreSendOptions
+	fn   PreSendFunc
+}
+
+type preSendResp struct {
+	Valid   bool            `json:"valid"`
+	Code    string          `json:"code,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewPreSendHandler 创建发送前回调处理器
+func NewPreSendHandler(opts PreSendOptions, fn PreSendFunc) *PreSendHandler {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultPreSendTimeout
+	}
+
+	if opts.Fallback == nil {
+		fallback := Pass()
+		opts.Fallback = &fallback
+	}
+
+	return &PreSendHandler{opts: opts, fn: fn}
+}
+
+// ServeHTTP 处理发送前回调请求
+func (h *PreSendHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := decodeEvent(limitBody(w, r), h.opts.Secret, h.opts.MaxAge)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	decision := *h.opts.Fallback
+	if msg, err := toMessage(event); err == nil {
+		decision = h.decide(r.Context(), msg)
+	}
+
+	buf, err := h.encode(decision)
+	if err != nil {
+		if buf, err = h.encode(*h.opts.Fallback); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf)
+}
+
+// 在超时时间内调用处理函数，超时或处理函数异常时使用兜底结果
+func (h *PreSendHandler) decide(ctx context.Context, msg *message.Message) Decision {
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+
+	ch := make(chan Decision, 1)
+	go func() {
+		defer func() {
+			if recover() != nil {
+				ch <- *h.opts.Fallback
+			}
+		}()
+		ch <- h.fn(ctx, msg)
+	}()
+
+	select {
+	case decision := <-ch:
+		// 处理函数可能在感知到上下文结束后才返回，此时视为超时
+		if ctx.Err() != nil {
+			return *h.opts.Fallback
+		}
+		return decision
+	case <-ctx.Done():
+		return *h.opts.Fallback
+	}
+}
+
+// 编码应答内容
+func (h *PreSendHandler) encode(decision Decision) ([]byte, error) {
+	resp := &preSendResp{Valid: decision.valid, Code: decision.code}
+	if decision.valid && decision.message != nil {
+		payload, err := message.EncodePayload(decision.message, h.opts.BaseUrl)
+		if err != nil {
+			return nil, err
+		}
+		resp.Payload = payload
+	}
+
+	return json.Marshal(resp)
+}
+
+// 将回调事件中的消息解析为消息，仅使用第一个消息体
+func toMessage(event *Event) (*message.Message, error) {
+	payload, err := event.Message()
+	if err != nil {
+		return nil, err
+	}
+
+	var target message.Target
+	switch event.ChatType {
+	case "chat":
+		target = message.TargetUser
+	case "groupchat":
+		target = message.TargetGroup
+	case "chatroom":
+		target = message.TargetChatroom
+	default:
+		return nil, fmt.Errorf("unsupported chat type %q", event.ChatType)
+	}
+
+	msg, err := payload.Message(target)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.SetSender(event.From)
+	msg.SetReceivers(event.To)
+
+	return msg, nil
+}
@@ -0,0 +1,75 @@
+package callback_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dobyte/easemob-im-server-sdk/callback"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreSendHandler(t *testing.T) {
+	handler := callback.NewPreSendHandler(callback.PreSendOptions{Secret: testSecret, Timeout: 100 * time.Millisecond}, func(ctx context.Context, msg *message.Message) callback.Decision {
+		body, ok := msg.GetBody().(*message.MsgTxt)
+		if !ok {
+			return callback.Pass()
+		}
+
+		switch body.Msg {
+		case "ok":
+			return callback.Pass()
+		case "bad":
+			return callback.Reject("HX:10001")
+		case "slow":
+			<-ctx.Done()
+			return callback.Reject("HX:10002")
+		}
+
+		modified, err := message.NewText(strings.ToUpper(body.Msg)).From(msg.GetSender()).ToUsers(msg.GetReceivers()...).Build()
+		if err != nil {
+			return callback.Pass()
+		}
+
+		return callback.Modify(modified)
+	})
+
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"hello", `{"valid":true,"payload":{"from":"test1","to":"test2","bodies":[{"msg":"HELLO","type":"txt"}]}}`},
+		{"{a:1}", `{"valid":true,"payload":{"from":"test1","to":"test2","bodies":[{"msg":"{A:1}","type":"txt"}]}}`},
+		{"ok", `{"valid":true}`},
+		{"bad", `{"valid":false,"code":"HX:10001"}`},
+		{"slow", `{"valid":true}`},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(newMessageEvent(c.text))))
+		if w.Code != http.StatusOK {
+			t.Fatal(w.Code, w.Body.String())
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("unexpected content type %q", ct)
+		}
+
+		var got, want interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(c.want), &want); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q responded %s, want %s", c.text, w.Body.String(), c.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package callback
+
+import (
+	"encoding/json"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+)
+
+type EventType string
+
+const (
+	EventMessage        EventType = "chat"         // 在线消息
+	EventOfflineMessage EventType = "chat_offline" // 离线消息
+	EventUserStatus     EventType = "userStatus"   // 用户登录、登出
+	EventGroupOperation EventType = "muc"          // 群组及聊天室操作，对应 eventType 为 chat 且 chat_type 为 muc 的回调
+)
+
+type Event struct {
+	CallID          string          `json:"callId"`          // 回调ID，格式为 {appkey}_{uuid}。
+	EventType       string          `json:"eventType"`       // 回调事件类型。
+	Timestamp       int64           `json:"timestamp"`       // 环信发送回调的时间，Unix 时间戳，单位为毫秒。
+	ChatType        string          `json:"chat_type"`       // 会话类型：- chat：单聊；- groupchat：群聊；- chatroom：聊天室；- muc：群组及聊天室操作。
+	GroupID         string          `json:"group_id"`        // 群组或聊天室ID。
+	From            string          `json:"from"`            // 消息发送方。
+	To              string          `json:"to"`              // 消息接收方。
+	MsgID           string          `json:"msg_id"`          // 消息ID。
+	AppKey          string          `json:"appkey"`          // 应用标识。
+	Host            string          `json:"host"`            // 服务器名称。
+	Security        string          `json:"security"`        // 签名，格式为 MD5(callId+secret+timestamp)。
+	SecurityVersion string          `json:"securityVersion"` // 签名版本。
+	User            string          `json:"user"`            // 用户状态事件的用户，格式为 {appkey}_{username}@{domain}/{resource}。
+	Status          string          `json:"status"`          // 用户状态：- online：上线；- offline：下线。
+	Reason          string          `json:"reason"`          // 用户状态变更原因，例如 login、logout、replaced。
+	OS              string          `json:"os"`              // 用户登录设备的操作系统。
+	IP              string          `json:"ip"`              // 用户登录设备的IP。
+	Version         string          `json:"version"`         // 用户登录的 SDK 版本。
+	Payload         json.RawMessage `json:"payload"`         // 回调内容，消息事件可通过 Message 解析，群组及聊天室操作事件可通过 GroupOperation 解析。
+}
+
+type GroupOperation struct {
+	MucID      string `json:"muc_id"`      // 群组或聊天室标识，格式为 {appkey}_{id}@conference.easemob.com。
+	Operation  string `json:"operation"`   // 操作类型，例如 create、destroy、join、leave、kick、ban、update。
+	Reason     string `json:"reason"`      // 操作原因。
+	IsChatroom bool   `json:"is_chatroom"` // 是否为聊天室操作。
+	Status     struct {
+		Description string `json:"description"` // 操作结果描述。
+		ErrorCode   string `json:"error_code"`  // 操作结果，ok 表示成功。
+	} `json:"status"` // 操作结果。
+}
+
+// Type 获取事件类型，群组及聊天室操作事件为 EventGroupOperation
+func (e *Event) Type() EventType {
+	if EventType(e.EventType) == EventMessage && e.ChatType == string(EventGroupOperation) {
+		return EventGroupOperation
+	}
+
+	return EventType(e.EventType)
+}
+
+// Message 解析消息事件的消息内容
+func (e *Event) Message() (*message.Payload, error) {
+	payload := &message.Payload{}
+	if err := json.Unmarshal(e.Payload, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// GroupOperation 解析群组及聊天室操作事件的操作内容
+func (e *Event) GroupOperation() (*GroupOperation, error) {
+	operation := &GroupOperation{}
+	if err := json.Unmarshal(e.Payload, operation); err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
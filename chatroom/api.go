@@ -1,6 +1,7 @@
 package chatroom
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dobyte/easemob-im-server-sdk/internal/core"
@@ -42,6 +43,10 @@ const (
 )
 
 type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
 	// AddSuperAdmin 添加超级管理员
 	// 在即时通讯应用中，仅聊天室超级管理员具有在客户端创建聊天室的权限。
 	// 环信即时通讯 IM 提供多个管理超级管理员的接口，包括获取、添加、移除等。
@@ -265,6 +270,11 @@ func NewAPI(client core.Client) API {
 	return &api{client: client}
 }
 
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
 // AddSuperAdmin 添加超级管理员
 func (a *api) AddSuperAdmin(username string) (bool, error) {
 	req := &addSuperAdminReq{SuperAdmin: username}
@@ -0,0 +1,134 @@
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	fetchConversationsUri = "/user/%s/user_channels?%s"
+	deleteConversationUri = "/users/%s/user_channel"
+	pinConversationUri    = "/user/%s/user_channel_pin"
+)
+
+type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
+	// FetchConversations 分页获取用户的会话列表
+	// 按会话更新时间倒序分页获取用户在服务端的会话列表。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/conversation#分页获取单个用户的会话列表
+	FetchConversations(arg FetchConversationsArg) (*FetchConversationsRet, error)
+
+	// DeleteConversation 删除会话
+	// 删除用户在服务端的指定会话，deleteRoam 为 true 时同时删除该会话在服务端的漫游消息。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/conversation#单向删除会话
+	DeleteConversation(username, id string, peerType PeerType, deleteRoam bool) error
+
+	// PinConversation 置顶会话
+	// 将用户的指定会话置顶。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/conversation#置顶会话
+	PinConversation(username, id string, peerType PeerType) error
+
+	// UnpinConversation 取消置顶会话
+	// 取消用户指定会话的置顶。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/conversation#置顶会话
+	UnpinConversation(username, id string, peerType PeerType) error
+}
+
+type api struct {
+	client core.Client
+}
+
+func NewAPI(client core.Client) API {
+	return &api{client: client}
+}
+
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
+// FetchConversations 分页获取用户的会话列表
+func (a *api) FetchConversations(arg FetchConversationsArg) (*FetchConversationsRet, error) {
+	query := new(url.URL).Query()
+	if arg.Limit > 0 {
+		query.Set("limit", strconv.Itoa(arg.Limit))
+	}
+	if arg.Cursor != "" {
+		query.Set("cursor", arg.Cursor)
+	}
+
+	resp := &fetchConversationsResp{}
+	if err := a.client.Get(fmt.Sprintf(fetchConversationsUri, arg.Username, query.Encode()), nil, resp); err != nil {
+		return nil, err
+	}
+
+	ret := &FetchConversationsRet{
+		List:    make([]*Conversation, 0, len(resp.Data.ChannelInfos)),
+		HasMore: resp.Data.Cursor != "",
+		Cursor:  resp.Data.Cursor,
+	}
+	for _, info := range resp.Data.ChannelInfos {
+		lastMessage, err := message.DecodePayload(info.LastMessage)
+		if err != nil {
+			return nil, fmt.Errorf("decode last message of %s: %w", info.ChannelID, err)
+		}
+
+		ret.List = append(ret.List, &Conversation{
+			ID:          parseChannelID(info.ChannelID),
+			ChannelID:   info.ChannelID,
+			Type:        info.Type,
+			UnreadNum:   info.UnreadNum,
+			UpdateTime:  info.UpdateTime,
+			Pinned:      info.IsPinned,
+			PinnedTime:  info.PinnedTime,
+			Meta:        info.Meta,
+			LastMessage: lastMessage,
+		})
+	}
+
+	return ret, nil
+}
+
+// DeleteConversation 删除会话
+func (a *api) DeleteConversation(username, id string, peerType PeerType, deleteRoam bool) error {
+	req := &deleteConversationReq{Channel: id, Type: peerType, DeleteRoam: deleteRoam}
+	return a.client.Delete(fmt.Sprintf(deleteConversationUri, username), req, nil)
+}
+
+// PinConversation 置顶会话
+func (a *api) PinConversation(username, id string, peerType PeerType) error {
+	req := &pinConversationReq{ConversationID: id, ConversationType: peerType, IsPinned: true}
+	return a.client.Post(fmt.Sprintf(pinConversationUri, username), req, nil)
+}
+
+// UnpinConversation 取消置顶会话
+func (a *api) UnpinConversation(username, id string, peerType PeerType) error {
+	req := &pinConversationReq{ConversationID: id, ConversationType: peerType, IsPinned: false}
+	return a.client.Post(fmt.Sprintf(pinConversationUri, username), req, nil)
+}
+
+// 从 {appkey}_{id}@{domain} 格式的会话标识中提取会话ID
+func parseChannelID(channelID string) string {
+	id := channelID
+	if i := strings.IndexByte(id, '@'); i >= 0 {
+		id = id[:i]
+	}
+
+	if i := strings.IndexByte(id, '_'); i >= 0 && strings.Contains(id[:i], "#") {
+		id = id[i+1:]
+	}
+
+	return id
+}
@@ -0,0 +1,62 @@
+package conversation
+
+import (
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPI_FetchConversations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/app/user/u1/user_channels" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		w.Write([]byte(`{"data":{"channel_infos":[` +
+			`{"channel_id":"org#app_u2@easemob.com","type":"chat","last_message":"{\"from\":\"u2\",\"to\":\"u1\",\"bodies\":[{\"type\":\"txt\",\"msg\":\"hello\"}]}"},` +
+			`{"channel_id":"org#app_g1@conference.easemob.com","type":"groupchat","last_message":{"from":"u1","to":"g1","bodies":[{"type":"txt","msg":"hi"}]}},` +
+			`{"channel_id":"org#app_r1@conference.easemob.com","type":"chatroom","last_message":""}` +
+			`],"cursor":"c1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := core.NewClient(&core.Options{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+		AppKey: "org#app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := NewAPI(client).FetchConversations(FetchConversationsArg{Username: "u1", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ret.List) != 3 || !ret.HasMore || ret.Cursor != "c1" {
+		t.Fatalf("unexpected result %+v", ret)
+	}
+
+	for i, want := range []string{"hello", "hi"} {
+		c := ret.List[i]
+		if c.LastMessage == nil || len(c.LastMessage.Bodies) != 1 {
+			t.Fatalf("unexpected last message of %s: %+v", c.ID, c.LastMessage)
+		}
+
+		if body, ok := c.LastMessage.Bodies[0].(*message.MsgTxt); !ok || body.Msg != want {
+			t.Fatalf("unexpected last message body of %s: %+v", c.ID, c.LastMessage.Bodies[0])
+		}
+	}
+
+	if ids := ret.List[0].ID + "," + ret.List[1].ID + "," + ret.List[2].ID; ids != "u2,g1,r1" {
+		t.Fatalf("unexpected conversation ids %s", ids)
+	}
+
+	if ret.List[2].LastMessage != nil {
+		t.Fatalf("expected no last message, got %+v", ret.List[2].LastMessage)
+	}
+}
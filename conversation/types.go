@@ -0,0 +1,68 @@
+package conversation
+
+import (
+	"encoding/json"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+)
+
+type PeerType string
+
+const (
+	PeerUser     PeerType = "chat"      // 单聊会话
+	PeerGroup    PeerType = "groupchat" // 群聊会话
+	PeerChatroom PeerType = "chatroom"  // 聊天室会话
+)
+
+type Conversation struct {
+	ID          string                 `json:"id"`           // 会话ID，单聊为对端用户名，群聊为群组ID，聊天室为聊天室ID。
+	ChannelID   string                 `json:"channel_id"`   // 会话标识，格式为 {appkey}_{id}@{domain}。
+	Type        PeerType               `json:"type"`         // 会话类型。
+	UnreadNum   int                    `json:"unread_num"`   // 未读消息数。
+	UpdateTime  int64                  `json:"update_time"`  // 会话更新时间，Unix 时间戳，单位为毫秒。
+	Pinned      bool                   `json:"pinned"`       // 会话是否置顶。
+	PinnedTime  int64                  `json:"pinned_time"`  // 会话置顶时间，Unix 时间戳，单位为毫秒。
+	Meta        map[string]interface{} `json:"meta"`         // 会话扩展信息。
+	LastMessage *message.Payload       `json:"last_message"` // 会话中的最新一条消息，会话没有消息时为空。
+}
+
+type FetchConversationsArg struct {
+	Username string // （必填）用户名。
+	Limit    int    // （选填）每页获取的会话数量，取值范围为 [1,50]，默认为 10。
+	Cursor   string // （选填）开始获取数据的游标位置，首次请求不传，之后传入上一次请求返回的游标。
+}
+
+type FetchConversationsRet struct {
+	List    []*Conversation `json:"list"`
+	HasMore bool            `json:"has_more"`
+	Cursor  string          `json:"cursor"`
+}
+
+type channelInfo struct {
+	ChannelID   string                 `json:"channel_id"`
+	Type        PeerType               `json:"type"`
+	UnreadNum   int                    `json:"unread_num"`
+	UpdateTime  int64                  `json:"update_time"`
+	IsPinned    bool                   `json:"is_pinned"`
+	PinnedTime  int64                  `json:"pinned_time"`
+	Meta        map[string]interface{} `json:"meta"`
+	LastMessage json.RawMessage        `json:"last_message"`
+}
+
+type fetchConversationsResp struct {
+	Data struct {
+		ChannelInfos []*channelInfo `json:"channel_infos"`
+		Cursor       string         `json:"cursor"`
+	} `json:"data"`
+}
+
+type deleteConversationReq struct {
+	Channel    string   `json:"channel"`
+	Type       PeerType `json:"type"`
+	DeleteRoam bool     `json:"delete_roam"`
+}
+
+type pinConversationReq struct {
+	ConversationID   string   `json:"conversationId"`
+	ConversationType PeerType `json:"conversationType"`
+	IsPinned         bool     `json:"isPinned"`
+}
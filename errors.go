@@ -0,0 +1,33 @@
+package im
+
+import "github.com/dobyte/easemob-im-server-sdk/internal/core"
+
+// APIError 环信服务端返回的错误，可通过errors.As获取
+type APIError = core.APIError
+
+var (
+	ErrNotFound     = core.ErrNotFound     // 资源不存在
+	ErrUnauthorized = core.ErrUnauthorized // 鉴权失败
+	ErrRateLimited  = core.ErrRateLimited  // 请求频率超限
+	ErrDuplicate    = core.ErrDuplicate    // 资源重复
+)
+
+// IsNotFound 判断是否为资源不存在错误
+func IsNotFound(err error) bool {
+	return core.IsNotFound(err)
+}
+
+// IsUnauthorized 判断是否为鉴权失败错误
+func IsUnauthorized(err error) bool {
+	return core.IsUnauthorized(err)
+}
+
+// IsRateLimited 判断是否为请求频率超限错误
+func IsRateLimited(err error) bool {
+	return core.IsRateLimited(err)
+}
+
+// IsDuplicate 判断是否为资源重复错误
+func IsDuplicate(err error) bool {
+	return core.IsDuplicate(err)
+}
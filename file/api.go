@@ -0,0 +1,88 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"io"
+)
+
+const (
+	uploadUri   = "/chatfiles"
+	downloadUri = "/chatfiles/%s"
+)
+
+type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
+	// Upload 上传文件
+	// 上传图片、语音、视频或其他类型的文件，返回的文件 ID 及访问密钥用于发送相应类型的消息。
+	// 文件内容以流的方式上传，上传失败时不会自动重试，令牌失效导致的401也不会重新鉴权后再次上传。
+	// 上传请求不经过客户端中间件。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#上传文件
+	Upload(arg *UploadArg) (*UploadRet, error)
+
+	// Download 下载文件
+	// 以流的方式下载文件或缩略图，调用方读取完毕后需关闭返回的数据流。
+	// 下载请求不经过客户端中间件。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#下载文件
+	Download(arg *DownloadArg) (io.ReadCloser, error)
+}
+
+type api struct {
+	client core.Client
+}
+
+func NewAPI(client core.Client) API {
+	return &api{client: client}
+}
+
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
+// Upload 上传文件
+func (a *api) Upload(arg *UploadArg) (*UploadRet, error) {
+	if arg == nil || arg.Reader == nil {
+		return nil, errors.New("the reader of file is not set")
+	}
+
+	file := &core.File{Field: "file", Filename: arg.Filename, Reader: arg.Reader}
+	if arg.RestrictAccess {
+		file.Headers = map[string]string{"restrict-access": "true"}
+	}
+
+	resp := &uploadResp{}
+	if err := a.client.Upload(uploadUri, file, resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Entities) == 0 {
+		return nil, errors.New("the uploaded file is not returned")
+	}
+
+	return resp.Entities[0], nil
+}
+
+// Download 下载文件
+func (a *api) Download(arg *DownloadArg) (io.ReadCloser, error) {
+	if arg == nil || arg.UUID == "" {
+		return nil, errors.New("the uuid of file is not set")
+	}
+
+	headers := map[string]string{"Accept": "application/octet-stream"}
+	if arg.ShareSecret != "" {
+		headers["share-secret"] = arg.ShareSecret
+	}
+	if arg.Thumbnail {
+		headers["thumbnail"] = "true"
+	}
+
+	return a.client.Download(fmt.Sprintf(downloadUri, arg.UUID), headers)
+}
@@ -0,0 +1,109 @@
+package file
+
+import (
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 创建请求指向测试服务的文件接口
+func newTestAPI(t *testing.T, handler http.HandlerFunc) API {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := core.NewClient(&core.Options{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+		AppKey: "org#app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewAPI(client)
+}
+
+func TestAPI_Upload(t *testing.T) {
+	var restrictAccess []string
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/org/app/chatfiles" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		restrictAccess = append(restrictAccess, r.Header.Get("restrict-access"))
+
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("read form file: %v", err)
+			return
+		}
+		defer f.Close()
+
+		content, _ := io.ReadAll(f)
+		if header.Filename != "a.jpg" || string(content) != "image" {
+			t.Errorf("unexpected file %q with content %q", header.Filename, content)
+		}
+
+		w.Write([]byte(`{"entities":[{"uuid":"u1","type":"chatfile","share-secret":"s1"}]}`))
+	})
+
+	ret, err := api.Upload(&UploadArg{Filename: "a.jpg", Reader: strings.NewReader("image"), RestrictAccess: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.UUID != "u1" || ret.Type != "chatfile" || ret.ShareSecret != "s1" {
+		t.Fatalf("unexpected result %+v", ret)
+	}
+
+	if _, err = api.Upload(&UploadArg{Filename: "a.jpg", Reader: strings.NewReader("image")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(restrictAccess) != 2 || restrictAccess[0] != "true" || restrictAccess[1] != "" {
+		t.Fatalf("unexpected restrict-access headers %q", restrictAccess)
+	}
+}
+
+func TestAPI_Download(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/app/chatfiles/u1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"file_not_found","error_description":"file not found"}`))
+			return
+		}
+
+		if got := r.Header.Get("Accept"); got != "application/octet-stream" {
+			t.Errorf("unexpected accept header %q", got)
+		}
+
+		w.Write([]byte(r.Header.Get("share-secret") + "|" + r.Header.Get("thumbnail")))
+	})
+
+	cases := []struct {
+		arg  *DownloadArg
+		want string
+	}{
+		{&DownloadArg{UUID: "u1"}, "|"},
+		{&DownloadArg{UUID: "u1", ShareSecret: "s1", Thumbnail: true}, "s1|true"},
+	}
+
+	for _, c := range cases {
+		body, err := api.Download(c.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(body)
+		body.Close()
+		if err != nil || string(content) != c.want {
+			t.Errorf("download %+v = %q, want %q, err %v", c.arg, content, c.want, err)
+		}
+	}
+
+	_, err := api.Download(&DownloadArg{UUID: "u2"})
+	if e, ok := err.(*core.APIError); !ok || e.StatusCode != http.StatusNotFound || e.Code != "file_not_found" {
+		t.Fatalf("expected an APIError for 404, got %v", err)
+	}
+}
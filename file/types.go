@@ -0,0 +1,25 @@
+package file
+
+import "io"
+
+type UploadArg struct {
+	Filename       string    // （必填）文件名。
+	Reader         io.Reader // （必填）文件内容，上传过程中流式读取。
+	RestrictAccess bool      // （选填）是否限制访问该文件。- true：是，下载时需要提供 share-secret；- （默认）false：否。
+}
+
+type UploadRet struct {
+	UUID        string `json:"uuid"`         // 文件 ID，发送图片、语音、视频及文件消息时使用。
+	Type        string `json:"type"`         // 文件类型，固定为 chatfile。
+	ShareSecret string `json:"share-secret"` // 文件访问密钥，下载时使用。
+}
+
+type uploadResp struct {
+	Entities []*UploadRet `json:"entities"`
+}
+
+type DownloadArg struct {
+	UUID        string // （必填）文件 ID。
+	ShareSecret string // （选填）文件访问密钥，上传时限制访问的文件必须提供。
+	Thumbnail   bool   // （选填）是否下载缩略图，仅对图片及视频有效。- true：是；- （默认）false：否。
+}
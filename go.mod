@@ -3,6 +3,6 @@ module github.com/dobyte/easemob-im-server-sdk
 go 1.16
 
 require (
-	github.com/dobyte/http v0.0.2 // indirect
-	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4 // indirect
+	github.com/dobyte/http v0.0.2
+	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4
 )
@@ -1,9 +1,11 @@
 package group
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"io"
 	"strings"
 )
 
@@ -17,8 +19,10 @@ const (
 	getAnnouncementUri       = "/chatgroups/%s/announcement"
 	updateAnnouncementUri    = "/chatgroups/%s/announcement"
 	getAllShareFilesUri      = "/chatgroups/%s/share_files"
+	uploadShareFileUri       = "/chatgroups/%s/share_files"
 	fetchShareFilesUri       = "/chatgroups/%s/share_files?pagenum=%d&pagesize=%d"
 	getShareFileUri          = "/chatgroups/%s/share_files/%s"
+	downloadShareFileUri     = "/chatgroups/%s/share_files/%s"
 	deleteShareFileUri       = "/chatgroups/%s/share_files/%s"
 	fetchMembersUri          = "/chatgroups/%s/users?pagenum=%d&pagesize=%d"
 	addMemberUri             = "/chatgroups/%s/users/%s"
@@ -50,6 +54,10 @@ const (
 )
 
 type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
 	// GetGroup 获取群组详情
 	// 可以获取一个或多个群组的详情。当获取多个群组的详情时，返回所有存在的群组的详情；对于不存在的群组，返回 “group id doesn’t exist”。
 	// 点击查看详细文档:
@@ -110,12 +118,24 @@ type API interface {
 	// https://docs-im.easemob.com/ccim/rest/group#获取群组共享文件
 	FetchShareFiles(arg FetchShareFilesArg) (*FetchShareFilesRet, error)
 
+	// UploadShareFile 上传群组共享文件
+	// 上传指定群组 ID 的群组共享文件，文件内容以流的方式上传，上传失败时不会自动重试。注意上传的文件大小不能超过 10 MB。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/group#上传群组共享文件
+	UploadShareFile(id, filename string, r io.Reader) (*ShareFile, error)
+
 	// GetShareFile 下载群组共享文件
 	// 根据指定的群组 ID 与 file_id 下载群组共享文件，file_id 是通过 获取群组共享文件 接口获取。
 	// 点击查看详细文档:
 	// https://docs-im.easemob.com/ccim/rest/group#下载群组共享文件
 	GetShareFile(groupID, fileID string) (*ShareFile, error)
 
+	// DownloadShareFile 流式下载群组共享文件
+	// 根据指定的群组 ID 与 file_id 以流的方式下载群组共享文件内容，调用方读取完毕后需关闭返回的数据流。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/group#下载群组共享文件
+	DownloadShareFile(groupID, fileID string) (io.ReadCloser, error)
+
 	// DeleteShareFile 删除群组共享文件
 	// 根据指定的群组 ID 与 file_id 删除群组共享文件，file_id 是通过 获取群组共享文件 接口获取。
 	// 点击查看详细文档:
@@ -320,6 +340,11 @@ func NewAPI(client core.Client) API {
 	return &api{client: client}
 }
 
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
 // GetGroup 获取群组详情
 func (a *api) GetGroup(id string) (*Group, error) {
 	resp := &getGroupResp{}
@@ -420,8 +445,18 @@ func (a *api) FetchShareFiles(arg FetchShareFilesArg) (*FetchShareFilesRet, erro
 }
 
 // UploadShareFile 上传群组共享文件
-func (a *api) UploadShareFile() (*ShareFile, error) {
-	return nil, nil
+func (a *api) UploadShareFile(id, filename string, r io.Reader) (*ShareFile, error) {
+	if r == nil {
+		return nil, errors.New("the reader of share file is not set")
+	}
+
+	resp := &uploadShareFileResp{}
+	file := &core.File{Field: "file", Filename: filename, Reader: r}
+	if err := a.client.Upload(fmt.Sprintf(uploadShareFileUri, id), file, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
 }
 
 // GetShareFile 下载群组共享文件
@@ -434,6 +469,12 @@ func (a *api) GetShareFile(groupID, fileID string) (*ShareFile, error) {
 	return resp.Data, nil
 }
 
+// DownloadShareFile 流式下载群组共享文件
+func (a *api) DownloadShareFile(groupID, fileID string) (io.ReadCloser, error) {
+	headers := map[string]string{"Accept": "application/octet-stream"}
+	return a.client.Download(fmt.Sprintf(downloadShareFileUri, groupID, fileID), headers)
+}
+
 // DeleteShareFile 删除群组共享文件
 func (a *api) DeleteShareFile(groupID, fileID string) error {
 	return a.client.Delete(fmt.Sprintf(deleteShareFileUri, groupID, fileID), nil, nil)
@@ -0,0 +1,94 @@
+package group
+
+import (
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 创建请求指向测试服务的群组接口
+func newTestAPI(t *testing.T, handler http.HandlerFunc) API {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := core.NewClient(&core.Options{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+		AppKey: "org#app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewAPI(client)
+}
+
+func TestAPI_UploadShareFile(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/org/app/chatgroups/g1/share_files" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("read form file: %v", err)
+			return
+		}
+		defer f.Close()
+
+		content, _ := io.ReadAll(f)
+		if header.Filename != "a.txt" || string(content) != "hello" {
+			t.Errorf("unexpected file %q with content %q", header.Filename, content)
+		}
+
+		w.Write([]byte(`{"data":{"file_id":"f1","file_name":"a.txt","file_owner":"u1","file_size":5,"file_url":"https://a-1.easemob.com/f1","group_id":"g1","created":1}}`))
+	})
+
+	file, err := api.UploadShareFile("g1", "a.txt", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ShareFile{FileID: "f1", FileName: "a.txt", FileOwner: "u1", FileSize: 5, FileURL: "https://a-1.easemob.com/f1", GroupID: "g1", Created: 1}
+	if file == nil || *file != want {
+		t.Fatalf("unexpected share file %+v", file)
+	}
+
+	if _, err = api.UploadShareFile("g1", "a.txt", nil); err == nil {
+		t.Fatal("expected an error without reader")
+	}
+}
+
+func TestAPI_DownloadShareFile(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/org/app/chatgroups/g1/share_files/f1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"file_not_found"}`))
+			return
+		}
+
+		if got := r.Header.Get("Accept"); got != "application/octet-stream" {
+			t.Errorf("unexpected accept header %q", got)
+		}
+
+		w.Write([]byte("hello"))
+	})
+
+	body, err := api.DownloadShareFile("g1", "f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(content) != "hello" {
+		t.Fatalf("unexpected content %q, err %v", content, err)
+	}
+
+	_, err = api.DownloadShareFile("g1", "f2")
+	if e, ok := err.(*core.APIError); !ok || e.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected an APIError for 404, got %v", err)
+	}
+}
@@ -113,6 +113,8 @@ type ShareFile struct {
 	FileName  string `json:"file_name"`
 	FileOwner string `json:"file_owner"`
 	FileSize  int    `json:"file_size"`
+	FileURL   string `json:"file_url,omitempty"`
+	GroupID   string `json:"group_id,omitempty"`
 	Created   int64  `json:"created"`
 }
 
@@ -127,6 +129,10 @@ type FetchShareFilesRet struct {
 	HasMore bool         `json:"has_more"`
 }
 
+type uploadShareFileResp struct {
+	Data *ShareFile `json:"data"`
+}
+
 type getShareFileResp struct {
 	Data *ShareFile `json:"data"`
 }
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"io"
+	"time"
+)
+
+const (
+	getDownloadUrlUri = "/chatmessages/%s"
+	timeLayout        = "2006010215"
+)
+
+// 环信按北京时间划分历史消息文件
+var location = time.FixedZone("CST", 8*60*60)
+
+type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
+	// GetDownloadUrl 获取历史消息文件下载地址
+	// 获取指定时间所在小时的历史消息文件下载地址，环信按北京时间的小时划分历史消息文件。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#获取历史消息记录
+	GetDownloadUrl(t time.Time) (string, error)
+
+	// Fetch 拉取历史消息
+	// 逐小时下载 [start, end) 时间段内的历史消息文件，流式解压并解析每一条消息后回调 fn，fn 返回错误时停止拉取并返回该错误。
+	// 没有历史消息的小时会被跳过。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#获取历史消息记录
+	Fetch(start, end time.Time, fn func(record *Record) error) error
+}
+
+type api struct {
+	client core.Client
+}
+
+func NewAPI(client core.Client) API {
+	return &api{client: client}
+}
+
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
+// GetDownloadUrl 获取历史消息文件下载地址
+func (a *api) GetDownloadUrl(t time.Time) (string, error) {
+	resp := &getDownloadUrlResp{}
+	if err := a.client.Get(fmt.Sprintf(getDownloadUrlUri, t.In(location).Format(timeLayout)), nil, resp); err != nil {
+		return "", err
+	}
+
+	if len(resp.Data) == 0 || resp.Data[0].Url == "" {
+		return "", nil
+	}
+
+	return resp.Data[0].Url, nil
+}
+
+// Fetch 拉取历史消息
+func (a *api) Fetch(start, end time.Time, fn func(record *Record) error) error {
+	if fn == nil {
+		return errors.New("the callback of history is not set")
+	}
+
+	for t := start.In(location).Truncate(time.Hour); t.Before(end); t = t.Add(time.Hour) {
+		url, err := a.GetDownloadUrl(t)
+		if err != nil {
+			if core.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if url == "" {
+			continue
+		}
+
+		if err = a.fetchFile(url, fn); err != nil {
+			return fmt.Errorf("fetch history of %s: %w", t.Format(timeLayout), err)
+		}
+	}
+
+	return nil
+}
+
+// 下载并解析单个历史消息文件
+func (a *api) fetchFile(url string, fn func(record *Record) error) error {
+	rc, err := a.client.DownloadUrl(url)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	gr, err := gzip.NewReader(rc)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	br := bufio.NewReader(gr)
+	for {
+		line, err := br.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			record := &Record{}
+			if err := json.Unmarshal(line, record); err != nil {
+				return err
+			}
+
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
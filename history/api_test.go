@@ -0,0 +1,104 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 压缩历史消息文件内容
+func gzipLines(t *testing.T, lines ...string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestAPI_Fetch(t *testing.T) {
+	files := map[string][]byte{
+		"/files/2024010110": gzipLines(t,
+			`{"msg_id":"1","timestamp":1,"direction":"outgoing","from":"u1","to":"u2","chat_type":"chat","payload":{"bodies":[{"type":"txt","msg":"hello"}]}}`,
+			``,
+			`{"msg_id":"2","timestamp":2,"direction":"outgoing","from":"u2","to":"g1","chat_type":"groupchat","payload":{"bodies":[{"type":"txt","msg":"world"}]}}`,
+			``,
+		),
+		"/files/2024010113": gzipLines(t,
+			`{"msg_id":"3","timestamp":3,"direction":"outgoing","from":"u1","to":"r1","chat_type":"chatroom","payload":{"bodies":[{"type":"txt","msg":"!"}]}}`,
+		),
+	}
+
+	var (
+		hours []string
+		srv   *httptest.Server
+	)
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, ok := files[r.URL.Path]; ok {
+			w.Write(data)
+			return
+		}
+
+		hour := strings.TrimPrefix(r.URL.Path, "/org/app/chatmessages/")
+		hours = append(hours, hour)
+
+		switch hour {
+		case "2024010110", "2024010113":
+			w.Write([]byte(`{"data":[{"url":"` + srv.URL + `/files/` + hour + `"}]}`))
+		case "2024010111":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"storage_object_not_found"}`))
+		default:
+			w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := core.NewClient(&core.Options{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+		AppKey: "org#app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []*Record
+	start := time.Date(2024, 1, 1, 10, 30, 0, 0, location)
+	err = NewAPI(client).Fetch(start.UTC(), start.Add(3*time.Hour), func(record *Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"2024010110", "2024010111", "2024010112", "2024010113"}; !reflect.DeepEqual(hours, want) {
+		t.Fatalf("fetched hours %v, want %v", hours, want)
+	}
+
+	var got []string
+	for _, record := range records {
+		body, ok := record.Payload.Bodies[0].(*message.MsgTxt)
+		if !ok {
+			t.Fatalf("unexpected body %T of record %s", record.Payload.Bodies[0], record.MsgID)
+		}
+		got = append(got, record.MsgID+":"+record.ChatType+":"+body.Msg)
+	}
+
+	if want := []string{"1:chat:hello", "2:groupchat:world", "3:chatroom:!"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("fetched records %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,19 @@
+package history
+
+import "github.com/dobyte/easemob-im-server-sdk/message"
+
+type getDownloadUrlResp struct {
+	Data []struct {
+		Url string `json:"url"`
+	} `json:"data"`
+}
+
+type Record struct {
+	MsgID     string           `json:"msg_id"`    // 消息ID。
+	Timestamp int64            `json:"timestamp"` // 消息发送时间，Unix 时间戳，单位为毫秒。
+	Direction string           `json:"direction"` // 消息方向，固定为 outgoing。
+	From      string           `json:"from"`      // 消息发送方。
+	To        string           `json:"to"`        // 消息接收方，单聊为用户名，群聊为群组ID，聊天室为聊天室ID。
+	ChatType  string           `json:"chat_type"` // 会话类型：- chat：单聊；- groupchat：群聊；- chatroom：聊天室。
+	Payload   *message.Payload `json:"payload"`   // 消息内容。
+}
@@ -1,12 +1,20 @@
 package im
 
 import (
+	"errors"
+	"fmt"
 	"github.com/dobyte/easemob-im-server-sdk/chatroom"
+	"github.com/dobyte/easemob-im-server-sdk/conversation"
+	"github.com/dobyte/easemob-im-server-sdk/file"
 	"github.com/dobyte/easemob-im-server-sdk/group"
+	"github.com/dobyte/easemob-im-server-sdk/history"
 	"github.com/dobyte/easemob-im-server-sdk/internal/core"
 	"github.com/dobyte/easemob-im-server-sdk/message"
 	"github.com/dobyte/easemob-im-server-sdk/push"
+	"github.com/dobyte/easemob-im-server-sdk/reaction"
 	"github.com/dobyte/easemob-im-server-sdk/user"
+	"log"
+	"strings"
 	"sync"
 )
 
@@ -21,14 +29,27 @@ type IM interface {
 	Group() group.API
 	// Chatroom 获取聊天室管理接口
 	Chatroom() chatroom.API
+	// File 获取文件上传下载接口
+	File() file.API
+	// History 获取历史消息接口
+	History() history.API
+	// Reaction 获取消息表情回复接口
+	Reaction() reaction.API
+	// Conversation 获取会话管理接口
+	Conversation() conversation.API
+	// Close 停止后台刷新令牌，不再使用IM实例时调用
+	Close() error
 }
 
 type Options struct {
-	Host         string
-	AppKey       string
-	ClientID     string
-	ClientSecret string
-	TokenTTL     int64
+	Host         string       // 环信服务域名，例如 a1.easemob.com
+	AppKey       string       // 应用标识，格式为 {org_name}#{app_name}
+	ClientID     string       // 应用的 Client ID
+	ClientSecret string       // 应用的 Client Secret
+	TokenTTL     int64        // 令牌有效期，单位为秒，为 0 时使用默认值 7200
+	RetryPolicy  *RetryPolicy // 重试策略，为空时不重试
+	RateLimiter  *RateLimiter // 客户端限流器，为空时不限流，可在多个IM实例间共享
+	TokenStore   TokenStore   // 令牌存储，为空时使用内存存储，多个进程共享同一存储时可减少令牌申请
 }
 
 type im struct {
@@ -54,22 +75,110 @@ type im struct {
 		once     sync.Once
 		instance chatroom.API
 	}
+	file struct {
+		once     sync.Once
+		instance file.API
+	}
+	history struct {
+		once     sync.Once
+		instance history.API
+	}
+	reaction struct {
+		once     sync.Once
+		instance reaction.API
+	}
+	conversation struct {
+		once     sync.Once
+		instance conversation.API
+	}
+}
+
+// New 创建IM实例，配置无效时返回错误
+func New(opts *Options, opt ...Option) (IM, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	return newIM(opts, opt...)
 }
 
-func NewIM(opts *Options) IM {
-	return &im{
-		client: core.NewClient(&core.Options{
-			Host:   opts.Host,
-			AppKey: opts.AppKey,
-		}),
-		authClient: core.NewAuthClient(&core.Options{
-			Host:         opts.Host,
-			AppKey:       opts.AppKey,
-			ClientID:     opts.ClientID,
-			ClientSecret: opts.ClientSecret,
-			TTL:          opts.TokenTTL,
-		}),
+// NewIM 创建IM实例，AppKey无效时终止进程
+//
+// Deprecated: 请使用New，以便处理配置错误。
+func NewIM(opts *Options, opt ...Option) IM {
+	i, err := newIM(opts, opt...)
+	if err != nil {
+		log.Fatal(err)
 	}
+
+	return i
+}
+
+func newIM(opts *Options, opt ...Option) (*im, error) {
+	o := &options{}
+	for _, fn := range opt {
+		fn(o)
+	}
+
+	client, err := core.NewClient(&core.Options{
+		Host:        opts.Host,
+		AppKey:      opts.AppKey,
+		RetryPolicy: opts.RetryPolicy,
+		RateLimiter: opts.RateLimiter,
+		Scheme:      o.scheme,
+		HTTPClient:  o.httpClient,
+		Proxy:       o.proxy,
+		Timeout:     o.timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authClient, err := core.NewAuthClient(&core.Options{
+		Host:         opts.Host,
+		AppKey:       opts.AppKey,
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		TTL:          opts.TokenTTL,
+		RetryPolicy:  opts.RetryPolicy,
+		RateLimiter:  opts.RateLimiter,
+		TokenStore:   opts.TokenStore,
+		Scheme:       o.scheme,
+		HTTPClient:   o.httpClient,
+		Proxy:        o.proxy,
+		Timeout:      o.timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &im{client: client, authClient: authClient}, nil
+}
+
+// 校验配置
+func (opts *Options) validate() error {
+	switch {
+	case opts == nil:
+		return errors.New("options is not set")
+	case strings.TrimSpace(opts.Host) == "":
+		return errors.New("invalid options: host is required")
+	case strings.Contains(opts.Host, "/"):
+		return fmt.Errorf("invalid options: host %q should not contain a scheme or path", opts.Host)
+	case strings.TrimSpace(opts.AppKey) == "":
+		return errors.New("invalid options: appKey is required")
+	case strings.TrimSpace(opts.ClientID) == "":
+		return errors.New("invalid options: clientID is required")
+	case strings.TrimSpace(opts.ClientSecret) == "":
+		return errors.New("invalid options: clientSecret is required")
+	case opts.TokenTTL < 0:
+		return fmt.Errorf("invalid options: tokenTTL %d should not be negative", opts.TokenTTL)
+	}
+
+	if args := strings.Split(opts.AppKey, "#"); len(args) != 2 || args[0] == "" || args[1] == "" {
+		return fmt.Errorf("invalid options: appKey %q should be in the format of {org_name}#{app_name}", opts.AppKey)
+	}
+
+	return nil
 }
 
 // User 获取用户管理接口
@@ -111,3 +220,44 @@ func (i *im) Chatroom() chatroom.API {
 	})
 	return i.chatroom.instance
 }
+
+// File 获取文件上传下载接口
+func (i *im) File() file.API {
+	i.file.once.Do(func() {
+		i.file.instance = file.NewAPI(i.authClient)
+	})
+	return i.file.instance
+}
+
+// History 获取历史消息接口
+func (i *im) History() history.API {
+	i.history.once.Do(func() {
+		i.history.instance = history.NewAPI(i.authClient)
+	})
+	return i.history.instance
+}
+
+// Reaction 获取消息表情回复接口
+func (i *im) Reaction() reaction.API {
+	i.reaction.once.Do(func() {
+		i.reaction.instance = reaction.NewAPI(i.authClient)
+	})
+	return i.reaction.instance
+}
+
+// Conversation 获取会话管理接口
+func (i *im) Conversation() conversation.API {
+	i.conversation.once.Do(func() {
+		i.conversation.instance = conversation.NewAPI(i.authClient)
+	})
+	return i.conversation.instance
+}
+
+// Close 停止后台刷新令牌，关闭后仍可发起请求，令牌将在请求时按需刷新
+func (i *im) Close() error {
+	if err := i.client.Close(); err != nil {
+		return err
+	}
+
+	return i.authClient.Close()
+}
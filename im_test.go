@@ -1,14 +1,27 @@
 package im_test
 
 import (
+	"context"
+	"errors"
 	"github.com/dobyte/easemob-im-server-sdk"
 	"github.com/dobyte/easemob-im-server-sdk/chatroom"
+	"github.com/dobyte/easemob-im-server-sdk/conversation"
+	"github.com/dobyte/easemob-im-server-sdk/file"
 	"github.com/dobyte/easemob-im-server-sdk/group"
+	"github.com/dobyte/easemob-im-server-sdk/history"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"github.com/dobyte/easemob-im-server-sdk/reaction"
 	"github.com/dobyte/easemob-im-server-sdk/user"
+	"io"
+	"strings"
 	"testing"
+	"time"
 )
 
-var sdk im.IM
+var (
+	sdk    im.IM
+	sdkErr error
+)
 
 const (
 	defaultChatroomID  = "188688613048322"
@@ -18,10 +31,12 @@ const (
 	defaultNewPassword = "456123"
 	defaultTemplate    = "test"
 	defaultGroupID     = "188864710901761"
+	defaultMsgID       = "1028442084794698104"
+	defaultReaction    = "emoji_40"
 )
 
 func init() {
-	sdk = im.NewIM(&im.Options{
+	sdk, sdkErr = im.New(&im.Options{
 		Host:         "a1.easemob.com",
 		AppKey:       "",
 		ClientID:     "",
@@ -29,7 +44,44 @@ func init() {
 	})
 }
 
+// 未配置环信应用时跳过依赖环信服务的测试
+func skipWithoutSDK(t *testing.T) {
+	if sdkErr != nil {
+		t.Skipf("easemob app is not configured: %v", sdkErr)
+	}
+}
+
+func TestIM_New(t *testing.T) {
+	_, err := im.New(&im.Options{
+		Host:         "a1.easemob.com",
+		AppKey:       "invalid",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	})
+	if err == nil {
+		t.Fatal("invalid appKey should be rejected")
+	}
+
+	t.Log(err)
+}
+
+func TestIM_New_WithOptions(t *testing.T) {
+	_, err := im.New(&im.Options{
+		Host:         "a1.easemob.com",
+		AppKey:       "org#app",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	}, im.WithScheme("ftp"), im.WithTimeout(5*time.Second))
+	if err == nil {
+		t.Fatal("unsupported scheme should be rejected")
+	}
+
+	t.Log(err)
+}
+
 func TestIM_User_Register(t *testing.T) {
+	skipWithoutSDK(t)
+
 	entity, err := sdk.User().RegisterUsers(user.User{
 		Username: defaultUsername1,
 		Password: defaultOldPassword,
@@ -47,6 +99,8 @@ func TestIM_User_Register(t *testing.T) {
 }
 
 func TestIM_User_Get(t *testing.T) {
+	skipWithoutSDK(t)
+
 	entity, err := sdk.User().GetUser(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -55,7 +109,37 @@ func TestIM_User_Get(t *testing.T) {
 	t.Logf("%+v", entity)
 }
 
+func TestIM_User_GetWithContext(t *testing.T) {
+	skipWithoutSDK(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entity, err := sdk.User().WithContext(ctx).GetUser(defaultUsername1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", entity)
+}
+
+func TestIM_User_GetNotFound(t *testing.T) {
+	skipWithoutSDK(t)
+
+	_, err := sdk.User().GetUser("not-exists-user")
+	if !im.IsNotFound(err) {
+		t.Fatal(err)
+	}
+
+	var apiErr *im.APIError
+	if errors.As(err, &apiErr) {
+		t.Logf("%+v", apiErr)
+	}
+}
+
 func TestIM_User_Delete(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().DeleteUser(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -65,6 +149,8 @@ func TestIM_User_Delete(t *testing.T) {
 }
 
 func TestIM_User_BatchDeleteUsers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	entities, err := sdk.User().DeleteUsers(2)
 	if err != nil {
 		t.Fatal(err)
@@ -74,6 +160,8 @@ func TestIM_User_BatchDeleteUsers(t *testing.T) {
 }
 
 func TestIM_User_BatchDeleteAllUsers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	entities, err := sdk.User().DeleteAllUsers()
 	if err != nil {
 		t.Fatal(err)
@@ -83,6 +171,8 @@ func TestIM_User_BatchDeleteAllUsers(t *testing.T) {
 }
 
 func TestIM_User_ModifyUserPassword(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().UpdatePassword(defaultUsername1, defaultNewPassword)
 	if err != nil {
 		t.Fatal(err)
@@ -92,6 +182,8 @@ func TestIM_User_ModifyUserPassword(t *testing.T) {
 }
 
 func TestIM_User_GetOnlineStatus(t *testing.T) {
+	skipWithoutSDK(t)
+
 	status, err := sdk.User().GetOnlineStatus(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -101,6 +193,8 @@ func TestIM_User_GetOnlineStatus(t *testing.T) {
 }
 
 func TestIM_User_BatchGetOnlineStatus(t *testing.T) {
+	skipWithoutSDK(t)
+
 	statuses, err := sdk.User().GetOnlineStatuses(defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -110,6 +204,8 @@ func TestIM_User_BatchGetOnlineStatus(t *testing.T) {
 }
 
 func TestIM_User_SetMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	chat := 10
 	groupchat := 10
 
@@ -126,6 +222,8 @@ func TestIM_User_SetMutes(t *testing.T) {
 }
 
 func TestIM_User_GetMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ret, err := sdk.User().GetMutes(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -135,6 +233,8 @@ func TestIM_User_GetMutes(t *testing.T) {
 }
 
 func TestIM_User_FetchMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ret, err := sdk.User().FetchMutes(user.FetchMutesArg{
 		PageNum:  1,
 		PageSize: 10,
@@ -147,6 +247,8 @@ func TestIM_User_FetchMutes(t *testing.T) {
 }
 
 func TestIM_User_GetOfflineMsgCount(t *testing.T) {
+	skipWithoutSDK(t)
+
 	count, err := sdk.User().GetOfflineMsgCount(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -156,6 +258,8 @@ func TestIM_User_GetOfflineMsgCount(t *testing.T) {
 }
 
 func TestIM_User_GetOfflineMsgStatus(t *testing.T) {
+	skipWithoutSDK(t)
+
 	status, err := sdk.User().GetOfflineMsgStatus(defaultUsername1, "123")
 	if err != nil {
 		t.Fatal(err)
@@ -165,6 +269,8 @@ func TestIM_User_GetOfflineMsgStatus(t *testing.T) {
 }
 
 func TestIM_User_DeactivateUser(t *testing.T) {
+	skipWithoutSDK(t)
+
 	entity, err := sdk.User().DeactivateUser(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -174,6 +280,8 @@ func TestIM_User_DeactivateUser(t *testing.T) {
 }
 
 func TestIM_User_ActivateUser(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().ActivateUser(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -183,6 +291,8 @@ func TestIM_User_ActivateUser(t *testing.T) {
 }
 
 func TestIM_User_OfflineUser(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.User().OfflineUser(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -192,6 +302,8 @@ func TestIM_User_OfflineUser(t *testing.T) {
 }
 
 func TestIM_User_AddFriend(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().AddFriend(defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -201,6 +313,8 @@ func TestIM_User_AddFriend(t *testing.T) {
 }
 
 func TestIM_User_RemoveFriend(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().RemoveFriend(defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -210,6 +324,8 @@ func TestIM_User_RemoveFriend(t *testing.T) {
 }
 
 func TestIM_User_GetFriends(t *testing.T) {
+	skipWithoutSDK(t)
+
 	friends, err := sdk.User().GetFriends(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -219,6 +335,8 @@ func TestIM_User_GetFriends(t *testing.T) {
 }
 
 func TestIM_User_AddBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().AddBlacklists(defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -228,6 +346,8 @@ func TestIM_User_AddBlacklists(t *testing.T) {
 }
 
 func TestIM_User_RemoveBlacklist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().RemoveBlacklist(defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -237,6 +357,8 @@ func TestIM_User_RemoveBlacklist(t *testing.T) {
 }
 
 func TestIM_User_GetBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	blacklists, err := sdk.User().GetBlacklists(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -246,6 +368,8 @@ func TestIM_User_GetBlacklists(t *testing.T) {
 }
 
 func TestIM_User_SetMetadata(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().SetMetadata(defaultUsername1, map[string]string{
 		"avatarurl": "http://www.baidu.com",
 	})
@@ -257,6 +381,8 @@ func TestIM_User_SetMetadata(t *testing.T) {
 }
 
 func TestIM_User_GetMetadata(t *testing.T) {
+	skipWithoutSDK(t)
+
 	metadata, err := sdk.User().GetMetadata(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -266,6 +392,8 @@ func TestIM_User_GetMetadata(t *testing.T) {
 }
 
 func TestIM_User_BatchGetMetadata(t *testing.T) {
+	skipWithoutSDK(t)
+
 	metadata, err := sdk.User().BatchGetMetadata([]string{
 		"avatarurl",
 	}, defaultUsername1)
@@ -277,6 +405,8 @@ func TestIM_User_BatchGetMetadata(t *testing.T) {
 }
 
 func TestIM_User_DeleteMetadata(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.User().DeleteMetadata(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -286,6 +416,8 @@ func TestIM_User_DeleteMetadata(t *testing.T) {
 }
 
 func TestIM_User_GetCapacity(t *testing.T) {
+	skipWithoutSDK(t)
+
 	capacity, err := sdk.User().GetCapacity()
 	if err != nil {
 		t.Fatal(err)
@@ -295,6 +427,8 @@ func TestIM_User_GetCapacity(t *testing.T) {
 }
 
 func TestIM_User_SetOfflinePushNickname(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().SetOfflinePushNickname(defaultUsername1, "test")
 	if err != nil {
 		t.Fatal(err)
@@ -304,6 +438,8 @@ func TestIM_User_SetOfflinePushNickname(t *testing.T) {
 }
 
 func TestIM_User_SetOfflinePushDisplayStyle(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().SetOfflinePushDisplayStyle(defaultUsername1, 1)
 	if err != nil {
 		t.Fatal(err)
@@ -313,6 +449,8 @@ func TestIM_User_SetOfflinePushDisplayStyle(t *testing.T) {
 }
 
 func TestIM_User_EnableOfflinePushNoDisturbing(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().EnableOfflinePushNoDisturbing(defaultUsername1, 8, 23)
 	if err != nil {
 		t.Fatal(err)
@@ -322,6 +460,8 @@ func TestIM_User_EnableOfflinePushNoDisturbing(t *testing.T) {
 }
 
 func TestIM_User_DisableOfflinePushNoDisturbing(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().DisableOfflinePushNoDisturbing(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -331,6 +471,8 @@ func TestIM_User_DisableOfflinePushNoDisturbing(t *testing.T) {
 }
 
 func TestIM_User_SetOfflinePushTargetedNoDisturbing(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().SetOfflinePushTargetedNoDisturbing(&user.SetOfflinePushTargetedNoDisturbingArg{
 		Username:       defaultUsername1,
 		ToType:         "user",
@@ -347,6 +489,8 @@ func TestIM_User_SetOfflinePushTargetedNoDisturbing(t *testing.T) {
 }
 
 func TestIM_User_GetOfflinePushTargetedNoDisturbing(t *testing.T) {
+	skipWithoutSDK(t)
+
 	noDisturbing, err := sdk.User().GetOfflinePushTargetedNoDisturbing(defaultUsername1, "user", defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -356,6 +500,8 @@ func TestIM_User_GetOfflinePushTargetedNoDisturbing(t *testing.T) {
 }
 
 func TestIM_User_SetOfflinePushLanguage(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.User().SetOfflinePushLanguage(defaultUsername1, "EU")
 	if err != nil {
 		t.Fatal(err)
@@ -365,6 +511,8 @@ func TestIM_User_SetOfflinePushLanguage(t *testing.T) {
 }
 
 func TestIM_User_GetOfflinePushLanguage(t *testing.T) {
+	skipWithoutSDK(t)
+
 	language, err := sdk.User().GetOfflinePushLanguage(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -374,6 +522,8 @@ func TestIM_User_GetOfflinePushLanguage(t *testing.T) {
 }
 
 func TestIm_User_GetJoinedChatrooms(t *testing.T) {
+	skipWithoutSDK(t)
+
 	chatrooms, err := sdk.User().GetJoinedChatrooms(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -385,6 +535,8 @@ func TestIm_User_GetJoinedChatrooms(t *testing.T) {
 }
 
 func TestIm_User_GetJoinedGroups(t *testing.T) {
+	skipWithoutSDK(t)
+
 	groups, err := sdk.User().GetJoinedGroups(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -395,7 +547,20 @@ func TestIm_User_GetJoinedGroups(t *testing.T) {
 	}
 }
 
+func TestIm_User_DeleteRoamingMessages(t *testing.T) {
+	skipWithoutSDK(t)
+
+	err := sdk.User().DeleteRoamingMessages(defaultUsername1, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("OK")
+}
+
 func TestIm_Push_GetTemplate(t *testing.T) {
+	skipWithoutSDK(t)
+
 	template, err := sdk.Push().GetTemplate(defaultTemplate)
 	if err != nil {
 		t.Fatal(err)
@@ -405,6 +570,8 @@ func TestIm_Push_GetTemplate(t *testing.T) {
 }
 
 func TestIm_Push_CreateTemplate(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Push().CreateTemplate(defaultTemplate, "你好,{0}", "推送测试,{0}")
 	if err != nil {
 		t.Fatal(err)
@@ -414,6 +581,8 @@ func TestIm_Push_CreateTemplate(t *testing.T) {
 }
 
 func TestIm_Push_DeleteTemplate(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Push().DeleteTemplate(defaultTemplate)
 	if err != nil {
 		t.Fatal(err)
@@ -423,6 +592,8 @@ func TestIm_Push_DeleteTemplate(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddSuperAdmin(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().AddSuperAdmin(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -432,6 +603,8 @@ func TestIm_Chatroom_AddSuperAdmin(t *testing.T) {
 }
 
 func TestIm_Chatroom_RevokeSuperAdmin(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Chatroom().RevokeSuperAdmin(defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -441,6 +614,8 @@ func TestIm_Chatroom_RevokeSuperAdmin(t *testing.T) {
 }
 
 func TestIm_Chatroom_FetchSuperAdmins(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ret, err := sdk.Chatroom().FetchSuperAdmins(chatroom.FetchSuperAdminsArg{
 		PageNum:  3,
 		PageSize: 1,
@@ -453,6 +628,8 @@ func TestIm_Chatroom_FetchSuperAdmins(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetAllChatrooms(t *testing.T) {
+	skipWithoutSDK(t)
+
 	chatrooms, err := sdk.Chatroom().GetAllChatrooms()
 	if err != nil {
 		t.Fatal(err)
@@ -464,6 +641,8 @@ func TestIm_Chatroom_GetAllChatrooms(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetChatrooms(t *testing.T) {
+	skipWithoutSDK(t)
+
 	chatrooms, err := sdk.Chatroom().GetChatrooms(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -475,6 +654,8 @@ func TestIm_Chatroom_GetChatrooms(t *testing.T) {
 }
 
 func TestIm_Chatroom_CreateChatroom(t *testing.T) {
+	skipWithoutSDK(t)
+
 	id, err := sdk.Chatroom().CreateChatroom(&chatroom.CreateChatRoomArg{
 		Name:        "testchatroom1",
 		Description: "This is a chat room for test",
@@ -489,6 +670,8 @@ func TestIm_Chatroom_CreateChatroom(t *testing.T) {
 }
 
 func TestIm_Chatroom_ModifyChatroom(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().UpdateChatroom(chatroom.UpdateChatroomArg{
 		ID:          defaultChatroomID,
 		Name:        "testchatroom2",
@@ -503,6 +686,8 @@ func TestIm_Chatroom_ModifyChatroom(t *testing.T) {
 }
 
 func TestIm_Chatroom_DeleteChatroom(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().DeleteChatroom(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -512,6 +697,8 @@ func TestIm_Chatroom_DeleteChatroom(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetAnnouncement(t *testing.T) {
+	skipWithoutSDK(t)
+
 	announcement, err := sdk.Chatroom().GetAnnouncement(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -521,6 +708,8 @@ func TestIm_Chatroom_GetAnnouncement(t *testing.T) {
 }
 
 func TestIm_Chatroom_UpdateAnnouncement(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Chatroom().UpdateAnnouncement(defaultChatroomID, "aaa")
 	if err != nil {
 		t.Fatal(err)
@@ -530,6 +719,8 @@ func TestIm_Chatroom_UpdateAnnouncement(t *testing.T) {
 }
 
 func TestIm_Chatroom_FetchMembers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	members, err := sdk.Chatroom().FetchMembers(chatroom.FetchMembersArg{
 		ID:       defaultChatroomID,
 		PageNum:  1,
@@ -543,6 +734,8 @@ func TestIm_Chatroom_FetchMembers(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddMember(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().AddMember(defaultChatroomID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -552,6 +745,8 @@ func TestIm_Chatroom_AddMember(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddMembers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	members, err := sdk.Chatroom().AddMembers(defaultChatroomID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -561,6 +756,8 @@ func TestIm_Chatroom_AddMembers(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveMember(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().RemoveMember(defaultChatroomID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -570,6 +767,8 @@ func TestIm_Chatroom_RemoveMember(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveMembers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Chatroom().RemoveMembers(defaultChatroomID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -581,6 +780,8 @@ func TestIm_Chatroom_RemoveMembers(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetAdmins(t *testing.T) {
+	skipWithoutSDK(t)
+
 	admins, err := sdk.Chatroom().GetAdmins(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -590,6 +791,8 @@ func TestIm_Chatroom_GetAdmins(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddAdmin(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().AddAdmin(defaultChatroomID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -599,6 +802,8 @@ func TestIm_Chatroom_AddAdmin(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveAdmin(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().RemoveAdmin(defaultChatroomID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -608,6 +813,8 @@ func TestIm_Chatroom_RemoveAdmin(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	blacklists, err := sdk.Chatroom().GetBlacklists(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -617,6 +824,8 @@ func TestIm_Chatroom_GetBlacklists(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddBlacklist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().AddBlacklist(defaultChatroomID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -626,6 +835,8 @@ func TestIm_Chatroom_AddBlacklist(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	results, err := sdk.Chatroom().AddBlacklists(defaultChatroomID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -637,6 +848,8 @@ func TestIm_Chatroom_AddBlacklists(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveBlacklist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().RemoveBlacklist(defaultChatroomID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -646,6 +859,8 @@ func TestIm_Chatroom_RemoveBlacklist(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Chatroom().RemoveBlacklists(defaultChatroomID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -657,6 +872,8 @@ func TestIm_Chatroom_RemoveBlacklists(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetWhitelists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	blacklists, err := sdk.Chatroom().GetWhitelists(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -666,6 +883,8 @@ func TestIm_Chatroom_GetWhitelists(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddWhitelist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().AddWhitelist(defaultChatroomID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -675,6 +894,8 @@ func TestIm_Chatroom_AddWhitelist(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddWhitelists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	results, err := sdk.Chatroom().AddWhitelists(defaultChatroomID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -686,6 +907,8 @@ func TestIm_Chatroom_AddWhitelists(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveWhitelist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ok, err := sdk.Chatroom().RemoveWhitelist(defaultChatroomID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -695,6 +918,8 @@ func TestIm_Chatroom_RemoveWhitelist(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveWhitelists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Chatroom().RemoveWhitelists(defaultChatroomID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -706,6 +931,8 @@ func TestIm_Chatroom_RemoveWhitelists(t *testing.T) {
 }
 
 func TestIm_Chatroom_GetMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	mutes, err := sdk.Chatroom().GetMutes(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -717,6 +944,8 @@ func TestIm_Chatroom_GetMutes(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Chatroom().AddMutes(defaultChatroomID, 5000, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -728,6 +957,8 @@ func TestIm_Chatroom_AddMutes(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Chatroom().RemoveMutes(defaultChatroomID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -739,6 +970,8 @@ func TestIm_Chatroom_RemoveMutes(t *testing.T) {
 }
 
 func TestIm_Chatroom_AddAllMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Chatroom().AddAllMutes(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -748,6 +981,8 @@ func TestIm_Chatroom_AddAllMutes(t *testing.T) {
 }
 
 func TestIm_Chatroom_RemoveAllMutes(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Chatroom().RemoveAllMutes(defaultChatroomID)
 	if err != nil {
 		t.Fatal(err)
@@ -757,6 +992,8 @@ func TestIm_Chatroom_RemoveAllMutes(t *testing.T) {
 }
 
 func TestIm_Group_GetGroup(t *testing.T) {
+	skipWithoutSDK(t)
+
 	detail, err := sdk.Group().GetGroup(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -766,6 +1003,8 @@ func TestIm_Group_GetGroup(t *testing.T) {
 }
 
 func TestIm_Group_CreateGroup(t *testing.T) {
+	skipWithoutSDK(t)
+
 	id, err := sdk.Group().CreateGroup(&group.CreateGroupArg{
 		Name:        "test-group",
 		Description: "this is a desc of group",
@@ -780,6 +1019,8 @@ func TestIm_Group_CreateGroup(t *testing.T) {
 }
 
 func TestIm_Group_UpdateGroup(t *testing.T) {
+	skipWithoutSDK(t)
+
 	name := "test-group-new"
 	description := "this is a desc of group"
 	maxUsers := 300
@@ -804,6 +1045,8 @@ func TestIm_Group_UpdateGroup(t *testing.T) {
 }
 
 func TestIm_Group_DeleteGroup(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().DeleteGroup(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -813,6 +1056,8 @@ func TestIm_Group_DeleteGroup(t *testing.T) {
 }
 
 func TestIm_Group_GetAllGroups(t *testing.T) {
+	skipWithoutSDK(t)
+
 	groups, err := sdk.Group().GetAllGroups()
 	if err != nil {
 		t.Fatal(err)
@@ -824,6 +1069,8 @@ func TestIm_Group_GetAllGroups(t *testing.T) {
 }
 
 func TestIm_Group_FetchGroups(t *testing.T) {
+	skipWithoutSDK(t)
+
 	ret, err := sdk.Group().FetchGroups(group.FetchGroupsArg{
 		Limit:  10,
 		Cursor: "",
@@ -840,6 +1087,8 @@ func TestIm_Group_FetchGroups(t *testing.T) {
 }
 
 func TestIm_Group_GetAnnouncement(t *testing.T) {
+	skipWithoutSDK(t)
+
 	announcement, err := sdk.Group().GetAnnouncement(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -849,6 +1098,8 @@ func TestIm_Group_GetAnnouncement(t *testing.T) {
 }
 
 func TestIm_Group_UpdateAnnouncement(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().UpdateAnnouncement(defaultGroupID, "aaa")
 	if err != nil {
 		t.Fatal(err)
@@ -858,6 +1109,8 @@ func TestIm_Group_UpdateAnnouncement(t *testing.T) {
 }
 
 func TestIm_Group_GetAllShareFiles(t *testing.T) {
+	skipWithoutSDK(t)
+
 	files, err := sdk.Group().GetAllShareFiles(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -868,7 +1121,33 @@ func TestIm_Group_GetAllShareFiles(t *testing.T) {
 	}
 }
 
+func TestIm_Group_UploadShareFile(t *testing.T) {
+	skipWithoutSDK(t)
+
+	file, err := sdk.Group().UploadShareFile(defaultGroupID, "test.txt", strings.NewReader("hello world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", file)
+
+	rc, err := sdk.Group().DownloadShareFile(defaultGroupID, file.FileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(string(data))
+}
+
 func TestIm_Group_FetchMembers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	members, err := sdk.Group().FetchMembers(group.FetchMembersArg{
 		ID:       defaultGroupID,
 		PageNum:  1,
@@ -882,6 +1161,8 @@ func TestIm_Group_FetchMembers(t *testing.T) {
 }
 
 func TestIm_Group_AddMember(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().AddMember(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -891,6 +1172,8 @@ func TestIm_Group_AddMember(t *testing.T) {
 }
 
 func TestIm_Group_AddMembers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	members, err := sdk.Group().AddMembers(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -900,6 +1183,8 @@ func TestIm_Group_AddMembers(t *testing.T) {
 }
 
 func TestIm_Group_RemoveMember(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().RemoveMember(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -909,6 +1194,8 @@ func TestIm_Group_RemoveMember(t *testing.T) {
 }
 
 func TestIm_Group_RemoveMembers(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Group().RemoveMembers(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -920,6 +1207,8 @@ func TestIm_Group_RemoveMembers(t *testing.T) {
 }
 
 func TestIm_Group_GetAdmins(t *testing.T) {
+	skipWithoutSDK(t)
+
 	admins, err := sdk.Group().GetAdmins(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -929,6 +1218,8 @@ func TestIm_Group_GetAdmins(t *testing.T) {
 }
 
 func TestIm_Group_AddAdmin(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().AddAdmin(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -938,6 +1229,8 @@ func TestIm_Group_AddAdmin(t *testing.T) {
 }
 
 func TestIm_Group_RemoveAdmin(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().RemoveAdmin(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -947,6 +1240,8 @@ func TestIm_Group_RemoveAdmin(t *testing.T) {
 }
 
 func TestIm_Group_TransferGroup(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().TransferGroup(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -956,6 +1251,8 @@ func TestIm_Group_TransferGroup(t *testing.T) {
 }
 
 func TestIm_Group_GetBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	blacklists, err := sdk.Group().GetBlacklists(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -965,6 +1262,8 @@ func TestIm_Group_GetBlacklists(t *testing.T) {
 }
 
 func TestIm_Group_AddBlacklist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().AddBlacklist(defaultGroupID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -974,6 +1273,8 @@ func TestIm_Group_AddBlacklist(t *testing.T) {
 }
 
 func TestIm_Group_AddBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	results, err := sdk.Group().AddBlacklists(defaultGroupID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -985,6 +1286,8 @@ func TestIm_Group_AddBlacklists(t *testing.T) {
 }
 
 func TestIm_Group_RemoveBlacklist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().RemoveBlacklist(defaultGroupID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -994,6 +1297,8 @@ func TestIm_Group_RemoveBlacklist(t *testing.T) {
 }
 
 func TestIm_Group_RemoveBlacklists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Group().RemoveBlacklists(defaultGroupID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -1005,6 +1310,8 @@ func TestIm_Group_RemoveBlacklists(t *testing.T) {
 }
 
 func TestIm_Group_GetWhitelists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	blacklists, err := sdk.Group().GetWhitelists(defaultGroupID)
 	if err != nil {
 		t.Fatal(err)
@@ -1014,6 +1321,8 @@ func TestIm_Group_GetWhitelists(t *testing.T) {
 }
 
 func TestIm_Group_AddWhitelist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().AddWhitelist(defaultGroupID, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -1023,6 +1332,8 @@ func TestIm_Group_AddWhitelist(t *testing.T) {
 }
 
 func TestIm_Group_RemoveWhitelist(t *testing.T) {
+	skipWithoutSDK(t)
+
 	err := sdk.Group().RemoveWhitelist(defaultGroupID, defaultUsername1)
 	if err != nil {
 		t.Fatal(err)
@@ -1032,6 +1343,8 @@ func TestIm_Group_RemoveWhitelist(t *testing.T) {
 }
 
 func TestIm_Group_RemoveWhitelists(t *testing.T) {
+	skipWithoutSDK(t)
+
 	rets, err := sdk.Group().RemoveWhitelists(defaultGroupID, defaultUsername1, defaultUsername2)
 	if err != nil {
 		t.Fatal(err)
@@ -1041,3 +1354,399 @@ func TestIm_Group_RemoveWhitelists(t *testing.T) {
 		t.Logf("%+v", ret)
 	}
 }
+
+func TestIm_Message_SendUsers(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetUser)
+	msg.SetSender(defaultUsername1)
+	msg.SetBody(&message.MsgTxt{Msg: "hello"})
+
+	rets, err := sdk.Message().SendUsers(msg, defaultUsername2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ret := range rets {
+		t.Logf("%+v", ret)
+	}
+}
+
+func TestIm_Message_Recall(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetUser)
+	msg.SetSender(defaultUsername1)
+	msg.SetBody(&message.MsgTxt{Msg: "hello"})
+
+	rets, err := sdk.Message().SendUsers(msg, defaultUsername2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := sdk.Message().Recall(message.RecallArg{
+		MsgID:  rets[defaultUsername2].MsgID,
+		Target: message.TargetUser,
+		To:     defaultUsername2,
+		From:   defaultUsername1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", ret)
+}
+
+func TestIm_Message_SendInvalidBody(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetUser)
+	msg.SetSender(defaultUsername1)
+	msg.SetBody(message.MsgCustom{CustomEvent: "invalid event"})
+
+	if _, err := sdk.Message().SendUsers(msg, defaultUsername2); err == nil {
+		t.Fatal("invalid customEvent should be rejected")
+	} else {
+		t.Log(err)
+	}
+}
+
+func TestIm_Message_BatchSend(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetUser)
+	msg.SetSender(defaultUsername1)
+	msg.SetBody(message.MsgTxt{Msg: "hello"})
+	msg.AddReceivers(defaultUsername2)
+
+	ret, err := sdk.Message().BatchSend(msg, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", ret)
+}
+
+func TestIm_Message_BroadcastUsers(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetUser)
+	msg.SetBody(message.MsgTxt{Msg: "hello everyone"})
+
+	id, err := sdk.Message().BroadcastUsers(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(id)
+}
+
+func TestIm_Message_BroadcastChatrooms(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetChatroom)
+	msg.SetBody(message.MsgTxt{Msg: "hello everyone"})
+
+	id, err := sdk.Message().BroadcastChatrooms(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(id)
+}
+
+func TestIm_Message_Builder(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg, err := message.NewText("hello").
+		From(defaultUsername1).
+		ToGroup(defaultGroupID).
+		SyncDevice().
+		Ext(map[string]string{"k": "v"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rets, err := sdk.Message().Send(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ret := range rets {
+		t.Logf("%+v", ret)
+	}
+}
+
+func TestIm_Message_SendWithPushOptions(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg, err := message.NewText("hello").
+		From(defaultUsername1).
+		ToUsers(defaultUsername2).
+		Push(message.PushOptions{
+			ForceNotification: true,
+			Template: &message.PushTemplate{
+				Name:        defaultTemplate,
+				TitleArgs:   []string{"title"},
+				ContentArgs: []string{"content"},
+			},
+		}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rets, err := sdk.Message().Send(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ret := range rets {
+		t.Logf("%+v", ret)
+	}
+}
+
+func TestIm_Message_SendThread(t *testing.T) {
+	skipWithoutSDK(t)
+
+	threadID, err := sdk.Group().CreateThread(group.CreateThreadArg{
+		GroupID: defaultGroupID,
+		Name:    "test-thread",
+		Owner:   defaultUsername1,
+		MsgID:   "1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := message.NewText("summary").From(defaultUsername1).ToThread(threadID).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgID, err := sdk.Message().SendThread(msg, threadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(msgID)
+}
+
+func TestIm_Message_SendGroup(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetGroup)
+	msg.SetSender(defaultUsername1)
+	msg.SetBody(&message.MsgTxt{Msg: "hello"})
+
+	rets, err := sdk.Message().SendGroup(msg, defaultGroupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ret := range rets {
+		t.Logf("%+v", ret)
+	}
+}
+
+func TestIm_Message_SendChatroom(t *testing.T) {
+	skipWithoutSDK(t)
+
+	msg := message.NewMessage(message.TargetChatroom)
+	msg.SetSender(defaultUsername1)
+	msg.SetBody(&message.MsgTxt{Msg: "hello"})
+
+	rets, err := sdk.Message().SendChatroom(msg, defaultChatroomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ret := range rets {
+		t.Logf("%+v", ret)
+	}
+}
+
+func TestIm_Message_RoamingMessages(t *testing.T) {
+	skipWithoutSDK(t)
+
+	it := sdk.Message().RoamingMessages(message.FetchRoamingMessagesArg{
+		Username:  defaultUsername1,
+		Peer:      defaultUsername2,
+		Target:    message.TargetUser,
+		StartTime: time.Now().Add(-24 * time.Hour),
+		EndTime:   time.Now(),
+		Direction: message.RoamingDirectionDown,
+		Limit:     10,
+	})
+
+	for it.Next() {
+		item := it.Message()
+		t.Logf("%s %d %s %+v", item.MsgID, item.Timestamp, item.Message.GetType(), item.Message.GetBody())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIm_Message_DeleteRoamingMessages(t *testing.T) {
+	skipWithoutSDK(t)
+
+	err := sdk.Message().DeleteRoamingMessages(defaultUsername1, defaultUsername2, message.TargetUser, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("OK")
+}
+
+func TestIM_File_UploadAndDownload(t *testing.T) {
+	skipWithoutSDK(t)
+
+	ret, err := sdk.File().Upload(&file.UploadArg{
+		Filename:       "test.txt",
+		Reader:         strings.NewReader("hello world"),
+		RestrictAccess: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(ret)
+
+	rc, err := sdk.File().Download(&file.DownloadArg{
+		UUID:        ret.UUID,
+		ShareSecret: ret.ShareSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(string(data))
+}
+
+func TestIM_History_Fetch(t *testing.T) {
+	skipWithoutSDK(t)
+
+	end := time.Now().Add(-time.Hour)
+	start := end.Add(-3 * time.Hour)
+
+	err := sdk.History().Fetch(start, end, func(record *history.Record) error {
+		t.Logf("%+v", record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIM_Reaction_AddReaction(t *testing.T) {
+	skipWithoutSDK(t)
+
+	ret, err := sdk.Reaction().AddReaction(defaultUsername1, defaultMsgID, defaultReaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", ret)
+}
+
+func TestIM_Reaction_GetReactions(t *testing.T) {
+	skipWithoutSDK(t)
+
+	rets, err := sdk.Reaction().GetReactions(reaction.GetReactionsArg{
+		Username: defaultUsername1,
+		MsgIDs:   []string{defaultMsgID},
+		MsgType:  message.TargetUser,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ret := range rets {
+		t.Logf("%+v", ret)
+	}
+}
+
+func TestIM_Reaction_FetchReactionUsers(t *testing.T) {
+	skipWithoutSDK(t)
+
+	ret, err := sdk.Reaction().FetchReactionUsers(reaction.FetchReactionUsersArg{
+		Username: defaultUsername1,
+		MsgID:    defaultMsgID,
+		Reaction: defaultReaction,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", ret)
+}
+
+func TestIM_Reaction_RemoveReaction(t *testing.T) {
+	skipWithoutSDK(t)
+
+	err := sdk.Reaction().RemoveReaction(defaultUsername1, defaultMsgID, defaultReaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("OK")
+}
+
+func TestIM_Conversation_FetchConversations(t *testing.T) {
+	skipWithoutSDK(t)
+
+	ret, err := sdk.Conversation().FetchConversations(conversation.FetchConversationsArg{
+		Username: defaultUsername1,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range ret.List {
+		t.Logf("%+v", item)
+	}
+}
+
+func TestIM_Conversation_PinConversation(t *testing.T) {
+	skipWithoutSDK(t)
+
+	err := sdk.Conversation().PinConversation(defaultUsername1, defaultUsername2, conversation.PeerUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("OK")
+}
+
+func TestIM_Conversation_UnpinConversation(t *testing.T) {
+	skipWithoutSDK(t)
+
+	err := sdk.Conversation().UnpinConversation(defaultUsername1, defaultUsername2, conversation.PeerUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("OK")
+}
+
+func TestIM_Conversation_DeleteConversation(t *testing.T) {
+	skipWithoutSDK(t)
+
+	err := sdk.Conversation().DeleteConversation(defaultUsername1, defaultGroupID, conversation.PeerGroup, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("OK")
+}
@@ -1,16 +1,34 @@
 package core
 
-import "golang.org/x/sync/singleflight"
+import (
+	"context"
+	"github.com/dobyte/http"
+	"golang.org/x/sync/singleflight"
+	"math/rand"
+	"sync"
+	"time"
+)
 
 const (
-	defaultGrantType = "client_credentials"
-	defaultAuthUri   = "/token"
-	defaultTokenTTL  = 7200
+	defaultGrantType      = "client_credentials"
+	defaultAuthUri        = "/token"
+	defaultTokenTTL       = 7200
+	tokenExpirySkew       = 10 * time.Second
+	maxTokenRefreshAhead  = 5 * time.Minute
+	tokenRefreshTimeout   = 30 * time.Second
+	tokenRefreshRetryWait = 30 * time.Second
 )
 
 type authClient struct {
 	*client
-	sfg singleflight.Group
+	sfg    singleflight.Group
+	key    string
+	store  TokenStore
+	mu     sync.Mutex
+	token  *Token
+	timer  *time.Timer
+	skew   time.Duration
+	closed bool
 }
 
 type getTokenReq struct {
@@ -26,34 +44,177 @@ type getTokenResp struct {
 	Application string `json:"application"`
 }
 
-func NewAuthClient(opts *Options) Client {
-	ac := &authClient{}
-	opts.unauthorizedHandler = func(c *client) error {
-		_, err, _ := ac.sfg.Do(opts.ClientID, func() (interface{}, error) {
-			req := &getTokenReq{
-				GrantType:    defaultGrantType,
-				ClientID:     opts.ClientID,
-				ClientSecret: opts.ClientSecret,
-				TTL:          opts.TTL,
-			}
-			resp := &getTokenResp{}
+func NewAuthClient(opts *Options) (Client, error) {
+	ac := &authClient{key: opts.AppKey + ":" + opts.ClientID, store: opts.TokenStore, skew: tokenExpirySkew}
+	if ac.store == nil {
+		ac.store = NewMemoryTokenStore()
+	}
+
+	opts.authorizeHandler = func(c *client) error {
+		if ac.currentToken().validAt(time.Now().Add(ac.expirySkew())) {
+			return nil
+		}
+		return ac.authorize(c)
+	}
+	opts.unauthorizedHandler = ac.authorize
+	opts.closeHandler = ac.close
+
+	c, err := NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	ac.client = c
+
+	return ac, nil
+}
+
+// 获取当前持有的令牌
+func (ac *authClient) currentToken() *Token {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
 
-			if req.TTL <= 0 {
-				req.TTL = defaultTokenTTL
-			}
+	return ac.token
+}
 
-			if err := c.Post(defaultAuthUri, req, resp); err != nil {
-				return nil, err
-			}
+// 获取令牌的过期提前量，令牌在过期前该时长内即视为失效
+func (ac *authClient) expirySkew() time.Duration {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
 
-			c.client.SetBearerToken(resp.AccessToken)
+	return ac.skew
+}
 
-			return nil, nil
-		})
+// 更新令牌，刷新在独立的上下文中进行，不受任一调用方取消的影响，各调用方在自身的上下文结束时放弃等待
+func (ac *authClient) authorize(c *client) error {
+	stale := ac.currentToken()
+	ch := ac.sfg.DoChan(ac.key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+		defer cancel()
 
+		return nil, ac.refresh(&client{base: c.base, ctx: ctx}, stale)
+	})
+
+	select {
+	case ret := <-ch:
+		return ret.Err
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+}
+
+// 替换失效的令牌，优先使用令牌存储中由其他进程更新的令牌
+func (ac *authClient) refresh(c *client, stale *Token) error {
+	token, err := ac.store.Get(c.ctx, ac.key)
+	if err != nil {
 		return err
 	}
-	ac.client = NewClient(opts)
 
-	return ac
+	if !token.validAt(time.Now().Add(ac.expirySkew())) || (stale != nil && token.AccessToken == stale.AccessToken) {
+		if token, err = ac.fetch(c); err != nil {
+			return err
+		}
+
+		if err = ac.store.Set(c.ctx, ac.key, token); err != nil {
+			return err
+		}
+	}
+
+	ac.apply(token)
+
+	return nil
+}
+
+// 向环信申请令牌
+func (ac *authClient) fetch(c *client) (*Token, error) {
+	req := &getTokenReq{
+		GrantType:    defaultGrantType,
+		ClientID:     c.opts.ClientID,
+		ClientSecret: c.opts.ClientSecret,
+		TTL:          c.opts.TTL,
+	}
+	resp := &getTokenResp{}
+
+	if req.TTL <= 0 {
+		req.TTL = defaultTokenTTL
+	}
+
+	if err := c.do(http.MethodPost, defaultAuthUri, req, resp, nil); err != nil {
+		return nil, err
+	}
+
+	token := &Token{AccessToken: resp.AccessToken}
+	if resp.ExpiresIn > 0 {
+		lifetime := time.Duration(resp.ExpiresIn) * time.Second
+		token.ExpiresAt = time.Now().Add(lifetime)
+
+		// 有效期较短的令牌按有效期的一半提前失效，避免每次请求都刷新令牌
+		skew := tokenExpirySkew
+		if lifetime/2 < skew {
+			skew = lifetime / 2
+		}
+
+		ac.mu.Lock()
+		ac.skew = skew
+		ac.mu.Unlock()
+	}
+
+	return token, nil
+}
+
+// 使用令牌并安排过期前的后台刷新
+func (ac *authClient) apply(token *Token) {
+	ac.setHeader(http.HeaderAuthorization, "Bearer "+token.AccessToken)
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.token = token
+
+	if ac.timer != nil {
+		ac.timer.Stop()
+		ac.timer = nil
+	}
+
+	if ac.closed || token.ExpiresAt.IsZero() {
+		return
+	}
+
+	// 提前量加入随机抖动，使共享令牌存储的多个进程错开刷新时间
+	lifetime := time.Until(token.ExpiresAt)
+	ahead := lifetime / 10
+	if ahead > maxTokenRefreshAhead {
+		ahead = maxTokenRefreshAhead
+	}
+	if ahead > 0 {
+		ahead += time.Duration(rand.Int63n(int64(ahead)/2 + 1))
+	}
+
+	ac.timer = time.AfterFunc(lifetime-ahead, ac.backgroundRefresh)
+}
+
+// 后台刷新令牌，失败时在令牌过期前稍后重试
+func (ac *authClient) backgroundRefresh() {
+	if err := ac.authorize(&client{base: ac.base, ctx: context.Background()}); err == nil {
+		return
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if !ac.closed && ac.token.validAt(time.Now().Add(tokenRefreshRetryWait+ac.skew)) {
+		ac.timer = time.AfterFunc(tokenRefreshRetryWait, ac.backgroundRefresh)
+	}
+}
+
+// 停止后台刷新令牌，此后令牌仅在请求时按需刷新
+func (ac *authClient) close() {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.closed = true
+
+	if ac.timer != nil {
+		ac.timer.Stop()
+		ac.timer = nil
+	}
 }
@@ -0,0 +1,211 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 创建指向测试服务的客户端配置，/token 返回 ttl 秒后过期的令牌，其余请求交由 handler 处理
+func newTestOptions(t *testing.T, ttl int64, tokenDelay time.Duration, handler nethttp.HandlerFunc) (*Options, *int32) {
+	var tokens int32
+
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if strings.HasSuffix(r.URL.Path, defaultAuthUri) {
+			n := atomic.AddInt32(&tokens, 1)
+			time.Sleep(tokenDelay)
+			fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":%d}`, n, ttl)
+			return
+		}
+
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return &Options{
+		Host:         strings.TrimPrefix(srv.URL, "http://"),
+		Scheme:       "http",
+		AppKey:       "org#app",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	}, &tokens
+}
+
+func TestAuthClient_AuthorizeIgnoresCallerCancellation(t *testing.T) {
+	opts, tokens := newTestOptions(t, 7200, 100*time.Millisecond, nil)
+
+	c, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var (
+		wg           sync.WaitGroup
+		errShort     error
+		errPatient   error
+		startShort   = make(chan struct{})
+		startPatient = make(chan struct{})
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		close(startShort)
+		errShort = c.WithContext(ctx).Get("/users", nil, nil)
+	}()
+	go func() {
+		defer wg.Done()
+		<-startShort
+		close(startPatient)
+		errPatient = c.Get("/users", nil, nil)
+	}()
+	<-startPatient
+	wg.Wait()
+
+	if !errors.Is(errShort, context.DeadlineExceeded) {
+		t.Fatalf("expected the short caller to stop at its own deadline, got %v", errShort)
+	}
+
+	if errPatient != nil {
+		t.Fatalf("expected the patient caller to get the refreshed token, got %v", errPatient)
+	}
+
+	if n := atomic.LoadInt32(tokens); n != 1 {
+		t.Fatalf("expected exactly one token request, got %d", n)
+	}
+}
+
+func TestAuthClient_BackgroundRefreshAndClose(t *testing.T) {
+	opts, tokens := newTestOptions(t, 1, 0, nil)
+
+	c, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.Get("/users", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(1200 * time.Millisecond)
+	if n := atomic.LoadInt32(tokens); n < 2 {
+		t.Fatalf("expected the token to be refreshed before it expires, got %d token requests", n)
+	}
+
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	n := atomic.LoadInt32(tokens)
+	time.Sleep(1200 * time.Millisecond)
+	if m := atomic.LoadInt32(tokens); m != n {
+		t.Fatalf("expected no background refresh after close, got %d more token requests", m-n)
+	}
+
+	if err = c.Get("/users", nil, nil); err != nil {
+		t.Fatalf("expected requests to refresh the token on demand after close, got %v", err)
+	}
+	if m := atomic.LoadInt32(tokens); m != n+1 {
+		t.Fatalf("expected one on-demand token request, got %d", m-n)
+	}
+}
+
+func TestAuthClient_Unauthorized(t *testing.T) {
+	var rejected int32
+	opts, tokens := newTestOptions(t, 7200, 0, func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			atomic.AddInt32(&rejected, 1)
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	c, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err = c.Get("/users", nil, nil); err != nil {
+		t.Fatalf("expected the request to succeed after re-authorization, got %v", err)
+	}
+
+	if r, n := atomic.LoadInt32(&rejected), atomic.LoadInt32(tokens); r != 1 || n != 2 {
+		t.Fatalf("expected 1 rejected request and 2 token requests, got %d and %d", r, n)
+	}
+}
+
+func TestAuthClient_SharedTokenStore(t *testing.T) {
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+
+	opts, tokens := newTestOptions(t, 7200, 0, nil)
+	opts.TokenStore = store
+
+	first, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := NewAuthClient(&Options{
+		Host:         opts.Host,
+		Scheme:       opts.Scheme,
+		AppKey:       opts.AppKey,
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		TokenStore:   store,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	if err = first.Get("/users", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = second.Get("/users", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := atomic.LoadInt32(tokens); n != 1 {
+		t.Fatalf("expected the second client to reuse the stored token, got %d token requests", n)
+	}
+}
+
+func TestAuthClient_ShortLivedToken(t *testing.T) {
+	opts, tokens := newTestOptions(t, 4, 0, nil)
+
+	c, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		if err = c.Get("/users", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := atomic.LoadInt32(tokens); n != 1 {
+		t.Fatalf("expected a token shorter than the expiry skew to be reused, got %d token requests", n)
+	}
+}
@@ -1,12 +1,16 @@
 package core
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"github.com/dobyte/http"
-	"log"
+	"io"
 	nethttp "net/http"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Options struct {
@@ -15,7 +19,16 @@ type Options struct {
 	ClientID            string
 	ClientSecret        string
 	TTL                 int64
+	RetryPolicy         *RetryPolicy
+	RateLimiter         *RateLimiter
+	TokenStore          TokenStore
+	Scheme              string
+	HTTPClient          *nethttp.Client
+	Proxy               *url.URL
+	Timeout             time.Duration
+	authorizeHandler    func(c *client) error
 	unauthorizedHandler func(c *client) error
+	closeHandler        func()
 }
 
 type Client interface {
@@ -23,6 +36,10 @@ type Client interface {
 	Use(middlewares ...http.MiddlewareFunc)
 	// BaseUrl 获取基础url
 	BaseUrl() string
+	// Context 获取请求上下文
+	Context() context.Context
+	// WithContext 获取绑定了上下文的客户端
+	WithContext(ctx context.Context) Client
 	// Get GET请求
 	Get(uri string, data interface{}, resp interface{}) error
 	// Post POST请求
@@ -33,34 +50,71 @@ type Client interface {
 	Patch(uri string, data interface{}, resp interface{}) error
 	// Delete DELETE请求
 	Delete(uri string, data interface{}, resp interface{}) error
+	// Upload 以multipart/form-data格式流式上传文件，不经过 Use 设置的中间件，收到401时也不会重新鉴权
+	Upload(uri string, file *File, resp interface{}) error
+	// Download 流式下载文件，调用方需关闭返回的数据流，不经过 Use 设置的中间件
+	Download(uri string, headers map[string]string) (io.ReadCloser, error)
+	// DownloadUrl 从外部地址流式下载文件，调用方需关闭返回的数据流
+	DownloadUrl(url string) (io.ReadCloser, error)
+	// Close 释放客户端持有的后台资源
+	Close() error
 }
 
 type client struct {
-	opts    *Options
-	client  *http.Client
-	baseUrl string
+	*base
+	ctx context.Context
 }
 
-func NewClient(opts *Options) *client {
+// 同一客户端的所有上下文副本共享的状态
+type base struct {
+	opts        *Options
+	baseUrl     string
+	client      nethttp.Client
+	mu          sync.RWMutex
+	headers     map[string]string
+	middlewares []http.MiddlewareFunc
+}
+
+func NewClient(opts *Options) (*client, error) {
 	args := strings.Split(opts.AppKey, "#")
-	if len(args) != 2 {
-		log.Fatal("invalid appKey")
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return nil, fmt.Errorf("invalid appKey %q, it should be in the format of {org_name}#{app_name}", opts.AppKey)
+	}
+
+	scheme := opts.Scheme
+	switch scheme {
+	case "":
+		scheme = "https"
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid scheme %q, it should be http or https", opts.Scheme)
 	}
 
-	c := new(client)
+	c := &client{base: &base{}, ctx: context.Background()}
 	c.opts = opts
-	c.baseUrl = "https://" + opts.Host + "/" + args[0] + "/" + args[1]
-	c.client = http.NewClient()
-	c.client.SetContentType(http.ContentTypeJson)
-	c.client.SetHeader("Accept", http.ContentTypeJson)
-	c.client.SetBaseUrl(c.baseUrl)
+	c.baseUrl = scheme + "://" + opts.Host + "/" + args[0] + "/" + args[1]
 
-	return c
+	if opts.HTTPClient != nil {
+		c.client = *opts.HTTPClient
+	} else {
+		c.client = http.NewClient().Client
+		if transport, ok := c.client.Transport.(*nethttp.Transport); ok && opts.Proxy != nil {
+			transport.Proxy = nethttp.ProxyURL(opts.Proxy)
+		}
+	}
+	c.headers = map[string]string{
+		http.HeaderContentType: http.ContentTypeJson,
+		"Accept":               http.ContentTypeJson,
+	}
+
+	return c, nil
 }
 
 // Use 设置中间件
 func (c *client) Use(middlewares ...http.MiddlewareFunc) {
-	c.client.Use(middlewares...)
+	c.mu.Lock()
+	c.middlewares = append(c.middlewares, middlewares...)
+	c.mu.Unlock()
 }
 
 // BaseUrl 获取基础url
@@ -68,6 +122,20 @@ func (c *client) BaseUrl() string {
 	return c.baseUrl
 }
 
+// Context 获取请求上下文
+func (c *client) Context() context.Context {
+	return c.ctx
+}
+
+// WithContext 获取绑定了上下文的客户端
+func (c *client) WithContext(ctx context.Context) Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &client{base: c.base, ctx: ctx}
+}
+
 // Get GET请求
 func (c *client) Get(uri string, data interface{}, resp interface{}) error {
 	return c.request(http.MethodGet, uri, data, resp)
@@ -93,38 +161,128 @@ func (c *client) Delete(uri string, data interface{}, resp interface{}) error {
 	return c.request(http.MethodDelete, uri, data, resp)
 }
 
+// Close 释放客户端持有的后台资源，客户端的所有上下文副本共享同一份资源
+func (c *client) Close() error {
+	if c.opts.closeHandler != nil {
+		c.opts.closeHandler()
+	}
+
+	return nil
+}
+
+// 设置请求头
+func (c *client) setHeader(key, value string) {
+	c.mu.Lock()
+	c.headers[key] = value
+	c.mu.Unlock()
+}
+
+// 创建本次请求使用的HTTP客户端
+func (c *client) newHttpClient(ctx context.Context) *http.Client {
+	hc := http.NewClient()
+	hc.Client = c.client
+	hc.SetBaseUrl(c.baseUrl)
+	hc.SetContext(ctx)
+
+	c.mu.RLock()
+	hc.SetHeaders(c.headers)
+	hc.Use(c.middlewares...)
+	c.mu.RUnlock()
+
+	return hc
+}
+
 // HTTP请求
 func (c *client) request(method string, uri string, data interface{}, resp interface{}) error {
-	for i := 0; i < 2; i++ {
-		res, err := c.client.Request(method, uri, data)
-		if err != nil {
+	if c.opts.authorizeHandler != nil {
+		if err := c.opts.authorizeHandler(c); err != nil {
 			return err
 		}
+	}
 
-		if res.Response.StatusCode == nethttp.StatusOK {
-			if resp == nil || reflect.ValueOf(resp).IsNil() {
-				return nil
+	return c.do(method, uri, data, resp, c.opts.unauthorizedHandler)
+}
+
+// 发起HTTP请求，鉴权失败时调用unauthorizedHandler后重试一次，其余失败按重试策略处理
+func (c *client) do(method string, uri string, data interface{}, resp interface{}, unauthorizedHandler func(c *client) error) error {
+	return c.retry(method, uri, unauthorizedHandler, func() (int, string, error) {
+		return c.send(method, uri, data, resp)
+	})
+}
+
+// 按限流器、鉴权及重试策略执行send，外部地址不受限流器约束，send返回响应状态码、Retry-After响应头及错误
+func (c *client) retry(method string, uri string, unauthorizedHandler func(c *client) error, send func() (int, string, error)) error {
+	authorized := false
+
+	for attempt := 1; ; attempt++ {
+		if !isAbsoluteUrl(uri) {
+			if err := c.opts.RateLimiter.Wait(c.ctx, method, uri); err != nil {
+				return err
 			}
+		}
 
-			return res.Scan(resp)
+		statusCode, retryAfter, err := send()
+		if err == nil {
+			return nil
 		}
 
-		if res.Response.StatusCode == nethttp.StatusUnauthorized {
-			if c.opts.unauthorizedHandler != nil && i < 1 {
-				if err = c.opts.unauthorizedHandler(c); err != nil {
-					return err
-				}
-				continue
+		if statusCode == nethttp.StatusUnauthorized && unauthorizedHandler != nil && !authorized {
+			if err = unauthorizedHandler(c); err != nil {
+				return err
 			}
+			authorized = true
+			attempt--
+			continue
 		}
 
-		errResp := &errorResp{}
-		if err = res.Scan(errResp); err != nil {
+		if c.ctx.Err() != nil || !c.opts.RetryPolicy.shouldRetry(method, statusCode, attempt) {
 			return err
 		}
 
-		return errors.New(errResp.ErrorDescription)
+		wait, ok := c.opts.RetryPolicy.backoff(attempt, retryAfter)
+		if !ok {
+			return err
+		}
+
+		if err = sleep(c.ctx, wait); err != nil {
+			return err
+		}
 	}
+}
 
-	return nil
+// 发起单次HTTP请求，返回响应状态码及Retry-After响应头，网络错误时状态码为0
+func (c *client) send(method string, uri string, data interface{}, resp interface{}) (int, string, error) {
+	ctx := c.ctx
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	res, err := c.newHttpClient(ctx).Request(method, uri, data)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Close()
+
+	if res.Response.StatusCode == nethttp.StatusOK {
+		if resp == nil || reflect.ValueOf(resp).IsNil() {
+			return res.Response.StatusCode, "", nil
+		}
+
+		return res.Response.StatusCode, "", res.Scan(resp)
+	}
+
+	apiErr := &APIError{StatusCode: res.Response.StatusCode}
+	errResp := &errorResp{}
+	if err = res.Scan(errResp); err == nil {
+		apiErr = newAPIError(res.Response.StatusCode, errResp)
+	}
+
+	return res.Response.StatusCode, res.Response.Header.Get("Retry-After"), apiErr
+}
+
+// 判断是否为包含协议的外部地址
+func isAbsoluteUrl(uri string) bool {
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://")
 }
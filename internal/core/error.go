@@ -1,5 +1,18 @@
 package core
 
+import (
+	"errors"
+	"fmt"
+	nethttp "net/http"
+)
+
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrDuplicate    = errors.New("duplicate resource")
+)
+
 type errorResp struct {
 	Error            string `json:"error"`
 	ErrorDescription string `json:"error_description"`
@@ -7,3 +20,72 @@ type errorResp struct {
 	Duration         int64  `json:"duration"`
 	Exception        string `json:"exception"`
 }
+
+// APIError 环信服务端返回的错误
+type APIError struct {
+	StatusCode  int    // HTTP状态码
+	Code        string // 环信错误码，对应响应中的 error 字段，例如 service_resource_not_found。
+	Description string // 错误描述，对应响应中的 error_description 字段。
+	Exception   string // 服务端异常类型，对应响应中的 exception 字段。
+	Timestamp   int64  // 请求的 Unix 时间戳，单位为毫秒。
+	Duration    int64  // 请求耗时，单位为毫秒。
+}
+
+func newAPIError(statusCode int, resp *errorResp) *APIError {
+	return &APIError{
+		StatusCode:  statusCode,
+		Code:        resp.Error,
+		Description: resp.ErrorDescription,
+		Exception:   resp.Exception,
+		Timestamp:   resp.Timestamp,
+		Duration:    resp.Duration,
+	}
+}
+
+// Error 实现error接口
+func (e *APIError) Error() string {
+	switch {
+	case e.Description != "":
+		return e.Description
+	case e.Code != "":
+		return e.Code
+	default:
+		return fmt.Sprintf("%d %s", e.StatusCode, nethttp.StatusText(e.StatusCode))
+	}
+}
+
+// Is 支持通过errors.Is与ErrNotFound、ErrUnauthorized、ErrRateLimited、ErrDuplicate进行比较
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == nethttp.StatusNotFound || e.Code == "service_resource_not_found" || e.Code == "resource_not_found"
+	case ErrUnauthorized:
+		return e.StatusCode == nethttp.StatusUnauthorized || e.Code == "unauthorized"
+	case ErrRateLimited:
+		return e.StatusCode == nethttp.StatusTooManyRequests || e.Code == "reach_limit"
+	case ErrDuplicate:
+		return e.Code == "duplicate_unique_property_exists"
+	default:
+		return false
+	}
+}
+
+// IsNotFound 判断是否为资源不存在错误
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized 判断是否为鉴权失败错误
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsRateLimited 判断是否为请求频率超限错误
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsDuplicate 判断是否为资源重复错误
+func IsDuplicate(err error) bool {
+	return errors.Is(err, ErrDuplicate)
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dobyte/http"
+	"io"
+	"mime/multipart"
+	nethttp "net/http"
+	"time"
+)
+
+// File 待上传的文件
+type File struct {
+	Field    string            // 表单字段名。
+	Filename string            // 文件名。
+	Reader   io.Reader         // 文件内容。
+	Headers  map[string]string // 附加的请求头。
+}
+
+// Upload 以multipart/form-data格式流式上传文件
+// 文件内容只能读取一次，因此上传失败时不会按重试策略重试，收到401时也不会重新鉴权后再次上传；
+// 请求直接由底层HTTP客户端发出，不经过 Use 设置的中间件。
+func (c *client) Upload(uri string, file *File, resp interface{}) error {
+	if c.opts.authorizeHandler != nil {
+		if err := c.opts.authorizeHandler(c); err != nil {
+			return err
+		}
+	}
+
+	if err := c.opts.RateLimiter.Wait(c.ctx, http.MethodPost, uri); err != nil {
+		return err
+	}
+
+	ctx := c.ctx
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile(file.Field, file.Filename)
+		if err == nil {
+			_, err = io.Copy(part, file.Reader)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := c.newRawRequest(ctx, http.MethodPost, uri, pr, file.Headers)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(http.HeaderContentType, mw.FormDataContentType())
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != nethttp.StatusOK {
+		return readAPIError(res)
+	}
+
+	if resp == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(resp)
+}
+
+// Download 流式下载文件，调用方需关闭返回的数据流
+// 超时时间仅约束收到响应头之前的阶段，读取数据流的过程受请求上下文控制。
+// 请求直接由底层HTTP客户端发出，不经过 Use 设置的中间件。
+func (c *client) Download(uri string, headers map[string]string) (io.ReadCloser, error) {
+	if c.opts.authorizeHandler != nil {
+		if err := c.opts.authorizeHandler(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.download(uri, c.opts.unauthorizedHandler, func(ctx context.Context) (*nethttp.Request, error) {
+		return c.newRawRequest(ctx, http.MethodGet, uri, nil, headers)
+	})
+}
+
+// DownloadUrl 从环信返回的外部地址流式下载文件，调用方需关闭返回的数据流
+// 外部地址通常已包含签名，请求不携带鉴权信息，也不受限流器约束。
+func (c *client) DownloadUrl(url string) (io.ReadCloser, error) {
+	return c.download(url, nil, func(ctx context.Context) (*nethttp.Request, error) {
+		return nethttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	})
+}
+
+// 按重试策略发起下载请求
+func (c *client) download(uri string, unauthorizedHandler func(c *client) error, newRequest func(ctx context.Context) (*nethttp.Request, error)) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := c.retry(http.MethodGet, uri, unauthorizedHandler, func() (int, string, error) {
+		ctx, cancel := context.WithCancel(c.ctx)
+		if c.opts.Timeout > 0 {
+			timer := time.AfterFunc(c.opts.Timeout, cancel)
+			defer timer.Stop()
+		}
+
+		req, err := newRequest(ctx)
+		if err != nil {
+			cancel()
+			return 0, "", err
+		}
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			cancel()
+			return 0, "", err
+		}
+
+		if res.StatusCode != nethttp.StatusOK {
+			defer cancel()
+			defer res.Body.Close()
+			return res.StatusCode, res.Header.Get("Retry-After"), readAPIError(res)
+		}
+
+		body = &cancelReadCloser{ReadCloser: res.Body, cancel: cancel}
+
+		return res.StatusCode, "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// 创建不经过JSON编解码的原始请求，附加的请求头会覆盖客户端的默认请求头
+func (c *client) newRawRequest(ctx context.Context, method, uri string, body io.Reader, headers map[string]string) (*nethttp.Request, error) {
+	req, err := nethttp.NewRequestWithContext(ctx, method, c.baseUrl+uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	c.mu.RUnlock()
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return req, nil
+}
+
+// 解析失败响应中的错误信息
+func readAPIError(res *nethttp.Response) error {
+	errResp := &errorResp{}
+	if err := json.NewDecoder(res.Body).Decode(errResp); err != nil {
+		return &APIError{StatusCode: res.StatusCode}
+	}
+
+	return newAPIError(res.StatusCode, errResp)
+}
+
+// 关闭时一并释放请求上下文的数据流
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelReadCloser) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
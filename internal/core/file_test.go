@@ -0,0 +1,101 @@
+package core
+
+import (
+	"errors"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_Upload(t *testing.T) {
+	opts, _ := newTestOptions(t, 7200, 0, func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/org/app/files" {
+			w.WriteHeader(nethttp.StatusNotFound)
+			w.Write([]byte(`{"error":"service_resource_not_found","error_description":"no such path"}`))
+			return
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("unexpected authorization %q", got)
+		}
+		if got := r.Header.Get("restrict-access"); got != "true" {
+			t.Errorf("unexpected restrict-access header %q", got)
+		}
+
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("read form file: %v", err)
+			return
+		}
+		defer f.Close()
+
+		content, _ := io.ReadAll(f)
+		if header.Filename != "a.txt" || string(content) != "hello" {
+			t.Errorf("unexpected file %q with content %q", header.Filename, content)
+		}
+
+		w.Write([]byte(`{"uuid":"u1"}`))
+	})
+
+	c, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	resp := struct {
+		UUID string `json:"uuid"`
+	}{}
+	file := &File{Field: "file", Filename: "a.txt", Reader: strings.NewReader("hello"), Headers: map[string]string{"restrict-access": "true"}}
+	if err = c.Upload("/files", file, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.UUID != "u1" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	err = c.Upload("/missing", &File{Field: "file", Filename: "a.txt", Reader: strings.NewReader("hello")}, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != nethttp.StatusNotFound || apiErr.Code != "service_resource_not_found" {
+		t.Fatalf("expected an APIError for 404, got %v", err)
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	opts, _ := newTestOptions(t, 7200, 0, func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/org/app/files/u1" {
+			w.WriteHeader(nethttp.StatusForbidden)
+			w.Write([]byte(`{"error":"forbidden_op","error_description":"share secret required"}`))
+			return
+		}
+
+		if got := r.Header.Get("share-secret"); got != "s1" {
+			t.Errorf("unexpected share-secret header %q", got)
+		}
+
+		w.Write([]byte("content"))
+	})
+
+	c, err := NewAuthClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	body, err := c.Download("/files/u1", map[string]string{"share-secret": "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(content) != "content" {
+		t.Fatalf("unexpected content %q, err %v", content, err)
+	}
+
+	_, err = c.Download("/files/u2", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != nethttp.StatusForbidden || apiErr.Description != "share secret required" {
+		t.Fatalf("expected an APIError for 403, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrLimitExceeded = fmt.Errorf("client-side rate limit exceeded: %w", ErrRateLimited)
+
+// RateLimit 限流规则
+type RateLimit struct {
+	QPS   float64 // 每秒允许的请求数。
+	Burst int     // 允许的突发请求数，即令牌桶容量，默认为 QPS 向上取整。
+}
+
+// RateLimiterOptions 限流器配置
+type RateLimiterOptions struct {
+	Limits   map[string]RateLimit // 按 URI 模板配置的限流规则。键为 URI 模板，可带请求方法前缀，例如 "/messages/chatgroups"、"POST /users"、"/users/%s"。
+	Default  *RateLimit           // 未匹配任何规则的请求所使用的限流规则，为空时不限流。
+	FailFast bool                 // 超出限流时是否立即返回 ErrLimitExceeded，默认阻塞等待至令牌可用。
+}
+
+// RateLimiter 客户端限流器，基于令牌桶实现，可在多个IM实例间共享
+type RateLimiter struct {
+	rules    []*rateRule
+	fallback *bucket
+	failFast bool
+}
+
+type rateRule struct {
+	method  string
+	pattern *regexp.Regexp
+	score   int
+	bucket  *bucket
+}
+
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// DefaultRateLimits 获取环信默认的接口限流规则
+// 环信按 App Key 维度对各 REST 接口限流，实际额度以控制台及套餐为准。
+func DefaultRateLimits() map[string]RateLimit {
+	return map[string]RateLimit{
+		"POST /users":              {QPS: 100},
+		"GET /users/%s":            {QPS: 100},
+		"DELETE /users/%s":         {QPS: 100},
+		"/messages/users":          {QPS: 100},
+		"/messages/chatgroups":     {QPS: 20},
+		"/messages/chatrooms":      {QPS: 100},
+		"POST /chatgroups":         {QPS: 100},
+		"/chatgroups/%s":           {QPS: 100},
+		"/chatgroups/%s/users":     {QPS: 100},
+		"/chatgroups/%s/users/%s":  {QPS: 100},
+		"POST /chatrooms":          {QPS: 100},
+		"/chatrooms/%s":            {QPS: 100},
+		"/chatrooms/%s/users":      {QPS: 100},
+		"/chatrooms/%s/users/%s":   {QPS: 100},
+		"POST /users/batch/status": {QPS: 50},
+	}
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(opts RateLimiterOptions) *RateLimiter {
+	l := &RateLimiter{
+		rules:    make([]*rateRule, 0, len(opts.Limits)),
+		failFast: opts.FailFast,
+	}
+
+	for key, limit := range opts.Limits {
+		method, template := "", strings.TrimSpace(key)
+		if i := strings.IndexByte(template, ' '); i > 0 {
+			method, template = strings.ToUpper(template[:i]), strings.TrimSpace(template[i+1:])
+		}
+
+		pattern, literals := compileTemplate(template)
+		rule := &rateRule{method: method, pattern: pattern, score: literals, bucket: newBucket(limit)}
+		if method != "" {
+			rule.score += 1 << 16
+		}
+		l.rules = append(l.rules, rule)
+	}
+
+	sort.SliceStable(l.rules, func(i, j int) bool {
+		return l.rules[i].score > l.rules[j].score
+	})
+
+	if opts.Default != nil {
+		l.fallback = newBucket(*opts.Default)
+	}
+
+	return l
+}
+
+// Wait 获取一次请求的令牌，阻塞模式下等待至令牌可用或上下文结束
+func (l *RateLimiter) Wait(ctx context.Context, method, uri string) error {
+	if l == nil {
+		return nil
+	}
+
+	b := l.match(method, uri)
+	if b == nil {
+		return nil
+	}
+
+	if l.failFast {
+		if !b.allow(time.Now()) {
+			return ErrLimitExceeded
+		}
+		return nil
+	}
+
+	d := b.reserve(time.Now())
+	if err := sleep(ctx, d); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// 匹配请求所属的令牌桶
+func (l *RateLimiter) match(method, uri string) *bucket {
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		uri = uri[:i]
+	}
+
+	for _, rule := range l.rules {
+		if rule.method != "" && rule.method != method {
+			continue
+		}
+
+		if rule.pattern.MatchString(uri) {
+			return rule.bucket
+		}
+	}
+
+	return l.fallback
+}
+
+// 将URI模板编译为正则表达式，返回模板中的字面量字符数
+func compileTemplate(template string) (*regexp.Regexp, int) {
+	if i := strings.IndexByte(template, '?'); i >= 0 {
+		template = template[:i]
+	}
+
+	var (
+		expr     strings.Builder
+		literals int
+	)
+	for i := 0; i < len(template); i++ {
+		if template[i] == '%' && i+1 < len(template) {
+			switch template[i+1] {
+			case 's':
+				expr.WriteString(`[^/]+`)
+				i++
+				continue
+			case 'd':
+				expr.WriteString(`-?\d+`)
+				i++
+				continue
+			}
+		}
+		expr.WriteString(regexp.QuoteMeta(template[i : i+1]))
+		literals++
+	}
+
+	return regexp.MustCompile("^" + expr.String() + "$"), literals
+}
+
+func newBucket(limit RateLimit) *bucket {
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.QPS))
+	}
+
+	return &bucket{rate: limit.QPS, burst: burst, tokens: burst}
+}
+
+// 按流逝时间补充令牌
+func (b *bucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// 尝试立即获取一个令牌
+func (b *bucket) allow(now time.Time) bool {
+	if b.rate <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// 预占一个令牌，返回令牌可用前需要等待的时间
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// 归还预占的令牌
+func (b *bucket) cancel() {
+	if b.rate <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+	b.mu.Unlock()
+}
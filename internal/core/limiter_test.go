@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"testing"
+	"time"
+)
+
+func TestCompileTemplate(t *testing.T) {
+	cases := []struct {
+		template string
+		uri      string
+		want     bool
+	}{
+		{"/users", "/users", true},
+		{"/users", "/users/u1", false},
+		{"/users/%s", "/users/u1", true},
+		{"/users/%s", "/users/u1/status", false},
+		{"/users/%s", "/users/", false},
+		{"/chatgroups/%s/users/%s", "/chatgroups/g1/users/u1", true},
+		{"/mutes?pageNum=%d", "/mutes", true},
+		{"/page/%d", "/page/-12", true},
+		{"/page/%d", "/page/abc", false},
+		{"/a.b", "/axb", false},
+	}
+
+	for _, c := range cases {
+		pattern, _ := compileTemplate(c.template)
+		if got := pattern.MatchString(c.uri); got != c.want {
+			t.Errorf("template %q matching %q = %v, want %v", c.template, c.uri, got, c.want)
+		}
+	}
+
+	if _, literals := compileTemplate("/users/%s/status"); literals != len("/users//status") {
+		t.Errorf("unexpected literal count %d", literals)
+	}
+}
+
+func TestRateLimiter_Match(t *testing.T) {
+	l := NewRateLimiter(RateLimiterOptions{
+		Limits: map[string]RateLimit{
+			"/users/%s":        {QPS: 1},
+			"/users/%s/status": {QPS: 2},
+			"DELETE /users/%s": {QPS: 3},
+		},
+		Default: &RateLimit{QPS: 4},
+	})
+
+	cases := []struct {
+		method string
+		uri    string
+		rate   float64
+	}{
+		{nethttp.MethodGet, "/users/u1", 1},
+		{nethttp.MethodGet, "/users/u1?limit=10", 1},
+		{nethttp.MethodGet, "/users/u1/status", 2},
+		{nethttp.MethodDelete, "/users/u1", 3},
+		{nethttp.MethodGet, "/chatgroups", 4},
+	}
+
+	for _, c := range cases {
+		if b := l.match(c.method, c.uri); b == nil || b.rate != c.rate {
+			t.Errorf("%s %s matched %+v, want rate %v", c.method, c.uri, b, c.rate)
+		}
+	}
+
+	if b := NewRateLimiter(RateLimiterOptions{}).match(nethttp.MethodGet, "/users"); b != nil {
+		t.Errorf("expected no bucket without rules or default, got %+v", b)
+	}
+}
+
+func TestRateLimiter_FailFast(t *testing.T) {
+	l := NewRateLimiter(RateLimiterOptions{
+		Limits:   map[string]RateLimit{"/users": {QPS: 1, Burst: 2}},
+		FailFast: true,
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background(), nethttp.MethodGet, "/users"); err != nil {
+			t.Fatalf("request %d within burst failed: %v", i, err)
+		}
+	}
+
+	err := l.Wait(context.Background(), nethttp.MethodGet, "/users")
+	if !errors.Is(err, ErrLimitExceeded) || !IsRateLimited(err) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+
+	if err = l.Wait(context.Background(), nethttp.MethodGet, "/chatgroups"); err != nil {
+		t.Fatalf("unmatched request should not be limited, got %v", err)
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(RateLimiterOptions{
+		Limits: map[string]RateLimit{"/users": {QPS: 20, Burst: 1}},
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), nethttp.MethodGet, "/users"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected blocking wait of about 100ms, got %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx, nethttp.MethodGet, "/users"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the context, got %v", err)
+	}
+
+	if err := (*RateLimiter)(nil).Wait(context.Background(), nethttp.MethodGet, "/users"); err != nil {
+		t.Fatalf("nil limiter should not limit, got %v", err)
+	}
+}
+
+func TestBucket_ReserveAndCancel(t *testing.T) {
+	now := time.Now()
+	b := newBucket(RateLimit{QPS: 10, Burst: 1})
+
+	if d := b.reserve(now); d != 0 {
+		t.Fatalf("first reservation should not wait, got %v", d)
+	}
+
+	if d := b.reserve(now); d != 100*time.Millisecond {
+		t.Fatalf("second reservation should wait 100ms, got %v", d)
+	}
+
+	b.cancel()
+	if d := b.reserve(now); d != 100*time.Millisecond {
+		t.Fatalf("cancelled reservation should be returned, got %v", d)
+	}
+
+	if !b.allow(now.Add(200 * time.Millisecond)) {
+		t.Fatal("bucket should be refilled after 200ms")
+	}
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts     = 3
+	defaultRetryInitialInterval = 200 * time.Millisecond
+	defaultRetryMaxInterval     = 5 * time.Second
+	defaultRetryMultiplier      = 2
+	defaultRetryJitter          = 0.2
+)
+
+var defaultIdempotentMethods = []string{
+	nethttp.MethodGet,
+	nethttp.MethodHead,
+	nethttp.MethodOptions,
+	nethttp.MethodPut,
+	nethttp.MethodDelete,
+}
+
+// RetryPolicy 重试策略
+// 幂等请求在网络错误、429及5xx时重试；非幂等请求仅在服务端明确拒绝处理（429、503）时重试。
+type RetryPolicy struct {
+	MaxAttempts       int           // 最大尝试次数（包含首次请求），小于等于 1 时不重试。
+	InitialInterval   time.Duration // 首次重试前的等待时间，默认为 200ms。
+	MaxInterval       time.Duration // 重试等待时间的上限，默认为 5s，服务端通过 Retry-After 要求等待更久时不再重试。
+	Multiplier        float64       // 每次重试等待时间的增长倍数，默认为 2。
+	Jitter            float64       // 等待时间的随机抖动比例，取值范围为 [0,1]，默认为 0.2。
+	IdempotentMethods []string      // 视为幂等的请求方法，默认为 GET、HEAD、OPTIONS、PUT、DELETE。
+}
+
+// DefaultRetryPolicy 获取默认的重试策略
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:       defaultRetryMaxAttempts,
+		InitialInterval:   defaultRetryInitialInterval,
+		MaxInterval:       defaultRetryMaxInterval,
+		Multiplier:        defaultRetryMultiplier,
+		Jitter:            defaultRetryJitter,
+		IdempotentMethods: defaultIdempotentMethods,
+	}
+}
+
+// 判断第attempt次请求失败后是否可以重试，statusCode为0时表示网络错误
+func (p *RetryPolicy) shouldRetry(method string, statusCode int, attempt int) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	switch statusCode {
+	case nethttp.StatusTooManyRequests, nethttp.StatusServiceUnavailable:
+		return true
+	case 0, nethttp.StatusInternalServerError, nethttp.StatusBadGateway, nethttp.StatusGatewayTimeout:
+		return p.isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+// 判断请求方法是否幂等
+func (p *RetryPolicy) isIdempotent(method string) bool {
+	methods := p.IdempotentMethods
+	if methods == nil {
+		methods = defaultIdempotentMethods
+	}
+
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 计算第attempt次请求失败后的等待时间，优先使用服务端返回的Retry-After，其超过等待时间上限时放弃重试
+func (p *RetryPolicy) backoff(attempt int, retryAfter string) (time.Duration, bool) {
+	initial, max, multiplier, jitter := p.InitialInterval, p.MaxInterval, p.Multiplier, p.Jitter
+	if max <= 0 {
+		max = defaultRetryMaxInterval
+	}
+
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return d, d <= max
+	}
+
+	if initial <= 0 {
+		initial = defaultRetryInitialInterval
+	}
+	if multiplier < 1 {
+		multiplier = defaultRetryMultiplier
+	}
+	if jitter < 0 || jitter > 1 {
+		jitter = defaultRetryJitter
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if d > float64(max) {
+		d = float64(max)
+	}
+	d -= d * jitter * rand.Float64()
+
+	return time.Duration(d), true
+}
+
+// 解析Retry-After响应头，支持秒数与HTTP日期两种格式
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := nethttp.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// 等待指定时间，上下文结束时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	nethttp "net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	p := DefaultRetryPolicy()
+
+	cases := []struct {
+		method     string
+		statusCode int
+		attempt    int
+		want       bool
+	}{
+		{nethttp.MethodGet, 0, 1, true},
+		{nethttp.MethodGet, nethttp.StatusInternalServerError, 1, true},
+		{nethttp.MethodGet, nethttp.StatusBadGateway, 2, true},
+		{nethttp.MethodGet, nethttp.StatusGatewayTimeout, 1, true},
+		{nethttp.MethodGet, nethttp.StatusServiceUnavailable, 3, false},
+		{nethttp.MethodGet, nethttp.StatusBadRequest, 1, false},
+		{nethttp.MethodGet, nethttp.StatusNotFound, 1, false},
+		{nethttp.MethodPost, 0, 1, false},
+		{nethttp.MethodPost, nethttp.StatusInternalServerError, 1, false},
+		{nethttp.MethodPost, nethttp.StatusTooManyRequests, 1, true},
+		{nethttp.MethodPost, nethttp.StatusServiceUnavailable, 1, true},
+		{"delete", nethttp.StatusBadGateway, 1, true},
+	}
+
+	for _, c := range cases {
+		if got := p.shouldRetry(c.method, c.statusCode, c.attempt); got != c.want {
+			t.Errorf("shouldRetry(%s, %d, %d) = %v, want %v", c.method, c.statusCode, c.attempt, got, c.want)
+		}
+	}
+
+	var nilPolicy *RetryPolicy
+	if nilPolicy.shouldRetry(nethttp.MethodGet, nethttp.StatusServiceUnavailable, 1) {
+		t.Error("nil policy should never retry")
+	}
+
+	custom := &RetryPolicy{MaxAttempts: 5, IdempotentMethods: []string{nethttp.MethodPost}}
+	if !custom.shouldRetry(nethttp.MethodPost, nethttp.StatusInternalServerError, 4) {
+		t.Error("custom idempotent method should be retried on 5xx")
+	}
+	if custom.shouldRetry(nethttp.MethodGet, nethttp.StatusInternalServerError, 1) {
+		t.Error("method outside custom idempotent methods should not be retried on 5xx")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 2}
+
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second, 10: time.Second} {
+		p.Jitter = 0
+		if got, ok := p.backoff(attempt, ""); !ok || got != want {
+			t.Errorf("backoff(%d) = %v %v, want %v", attempt, got, ok, want)
+		}
+
+		p.Jitter = 0.5
+		if got, ok := p.backoff(attempt, ""); !ok || got > want || got < want/2 {
+			t.Errorf("backoff(%d) with jitter = %v %v, want within [%v, %v]", attempt, got, ok, want/2, want)
+		}
+	}
+
+	if got, ok := p.backoff(1, "1"); !ok || got != time.Second {
+		t.Errorf("backoff with Retry-After 1 = %v %v, want 1s", got, ok)
+	}
+
+	if _, ok := p.backoff(1, "3600"); ok {
+		t.Error("backoff should give up when Retry-After exceeds MaxInterval")
+	}
+
+	if got, ok := p.backoff(1, "invalid"); !ok || got > 100*time.Millisecond {
+		t.Errorf("backoff with invalid Retry-After = %v %v, want the exponential interval", got, ok)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter(""); ok || d != 0 {
+		t.Errorf("empty value = %v %v", d, ok)
+	}
+
+	if d, ok := parseRetryAfter("3"); !ok || d != 3*time.Second {
+		t.Errorf("seconds value = %v %v", d, ok)
+	}
+
+	if _, ok := parseRetryAfter("-1"); ok {
+		t.Error("negative seconds should be rejected")
+	}
+
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("invalid value should be rejected")
+	}
+
+	future := time.Now().Add(10 * time.Second).UTC().Format(nethttp.TimeFormat)
+	if d, ok := parseRetryAfter(future); !ok || d <= 8*time.Second || d > 10*time.Second {
+		t.Errorf("future date = %v %v", d, ok)
+	}
+
+	past := time.Now().Add(-time.Hour).UTC().Format(nethttp.TimeFormat)
+	if d, ok := parseRetryAfter(past); !ok || d != 0 {
+		t.Errorf("past date = %v %v", d, ok)
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	var calls int32
+	opts, _ := newTestOptions(t, 7200, 0, func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		switch r.URL.Path {
+		case "/org/app/flaky":
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(nethttp.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/org/app/busy":
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(nethttp.StatusTooManyRequests)
+		default:
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(nethttp.StatusInternalServerError)
+		}
+	})
+	opts.RetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: 10 * time.Millisecond}
+
+	c, err := NewClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.Get("/flaky", nil, nil); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+	if n := atomic.SwapInt32(&calls, 0); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+
+	start := time.Now()
+	if err = c.Get("/busy", nil, nil); !IsRateLimited(err) {
+		t.Fatalf("expected a rate limited error, got %v", err)
+	}
+	if n := atomic.SwapInt32(&calls, 0); n != 1 || time.Since(start) > time.Second {
+		t.Fatalf("expected to give up immediately on a long Retry-After, got %d attempts in %v", n, time.Since(start))
+	}
+
+	if err = c.Post("/broken", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := atomic.SwapInt32(&calls, 0); n != 1 {
+		t.Fatalf("expected a non-idempotent request not to be retried on 500, got %d attempts", n)
+	}
+
+	if err = c.Get("/broken", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := atomic.SwapInt32(&calls, 0); n != 3 {
+		t.Fatalf("expected an idempotent request to be retried on 500, got %d attempts", n)
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Token 访问令牌
+type Token struct {
+	AccessToken string    `json:"access_token"` // 访问令牌。
+	ExpiresAt   time.Time `json:"expires_at"`   // 过期时间，为零值时表示不过期。
+}
+
+// 判断令牌在指定时间之后是否仍然有效
+func (t *Token) validAt(at time.Time) bool {
+	return t != nil && t.AccessToken != "" && (t.ExpiresAt.IsZero() || t.ExpiresAt.After(at))
+}
+
+// TokenStore 令牌存储
+// 多个进程共享同一个令牌存储时，只需其中一个进程向环信申请令牌。
+type TokenStore interface {
+	// Get 获取令牌，令牌不存在时返回nil
+	Get(ctx context.Context, key string) (*Token, error)
+	// Set 保存令牌
+	Set(ctx context.Context, key string, token *Token) error
+}
+
+type memoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]*Token
+}
+
+// NewMemoryTokenStore 创建基于内存的令牌存储
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[string]*Token)}
+}
+
+// Get 获取令牌
+func (s *memoryTokenStore) Get(ctx context.Context, key string) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tokens[key], nil
+}
+
+// Set 保存令牌
+func (s *memoryTokenStore) Set(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	s.tokens[key] = token
+	s.mu.Unlock()
+
+	return nil
+}
+
+type fileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTokenStore 创建基于文件的令牌存储
+// 令牌以JSON格式保存在指定文件中，写入时先写临时文件再原子替换，可供同一主机或共享存储上的多个进程使用。
+func NewFileTokenStore(path string) TokenStore {
+	return &fileTokenStore{path: path}
+}
+
+// Get 获取令牌
+func (s *fileTokenStore) Get(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens[key], nil
+}
+
+// Set 保存令牌
+func (s *fileTokenStore) Set(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+
+	buf, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(buf); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err = os.Rename(f.Name(), s.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
+
+// 读取文件中的全部令牌
+func (s *fileTokenStore) load() (map[string]*Token, error) {
+	tokens := make(map[string]*Token)
+
+	buf, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, err
+	}
+
+	if len(buf) == 0 {
+		return tokens, nil
+	}
+
+	if err = json.Unmarshal(buf, &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestToken_ValidAt(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		token *Token
+		want  bool
+	}{
+		{nil, false},
+		{&Token{}, false},
+		{&Token{AccessToken: "t"}, true},
+		{&Token{AccessToken: "t", ExpiresAt: now.Add(time.Minute)}, true},
+		{&Token{AccessToken: "t", ExpiresAt: now}, false},
+		{&Token{AccessToken: "t", ExpiresAt: now.Add(-time.Minute)}, false},
+	}
+
+	for _, c := range cases {
+		if got := c.token.validAt(now); got != c.want {
+			t.Errorf("%+v validAt = %v, want %v", c.token, got, c.want)
+		}
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	store := NewFileTokenStore(path)
+
+	token, err := store.Get(ctx, "a")
+	if err != nil || token != nil {
+		t.Fatalf("expected no token in a missing file, got %+v %v", token, err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err = store.Set(ctx, "a", &Token{AccessToken: "token-a", ExpiresAt: expiresAt}); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set(ctx, "b", &Token{AccessToken: "token-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err = NewFileTokenStore(path).Get(ctx, "a")
+	if err != nil || token == nil || token.AccessToken != "token-a" || !token.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected token a %+v %v", token, err)
+	}
+
+	token, err = store.Get(ctx, "b")
+	if err != nil || token == nil || token.AccessToken != "token-b" {
+		t.Fatalf("unexpected token b %+v %v", token, err)
+	}
+
+	matches, err := filepath.Glob(path + ".*.tmp")
+	if err != nil || len(matches) != 0 {
+		t.Fatalf("expected temporary files to be cleaned up, got %v %v", matches, err)
+	}
+
+	if err = os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get(ctx, "a"); err == nil {
+		t.Fatal("expected an error for a corrupted file")
+	}
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTokenStore()
+
+	if token, err := store.Get(ctx, "a"); err != nil || token != nil {
+		t.Fatalf("expected no token, got %+v %v", token, err)
+	}
+
+	if err := store.Set(ctx, "a", &Token{AccessToken: "token-a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := store.Get(ctx, "a"); err != nil || token == nil || token.AccessToken != "token-a" {
+		t.Fatalf("unexpected token %+v %v", token, err)
+	}
+}
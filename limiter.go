@@ -0,0 +1,26 @@
+package im
+
+import "github.com/dobyte/easemob-im-server-sdk/internal/core"
+
+// ErrLimitExceeded 超出客户端限流，可通过IsRateLimited判断
+var ErrLimitExceeded = core.ErrLimitExceeded
+
+// RateLimit 限流规则
+type RateLimit = core.RateLimit
+
+// RateLimiterOptions 限流器配置
+type RateLimiterOptions = core.RateLimiterOptions
+
+// RateLimiter 客户端限流器，基于令牌桶实现，可在多个IM实例间共享
+type RateLimiter = core.RateLimiter
+
+// NewRateLimiter 创建限流器
+// 限流规则按 URI 模板匹配，键可带请求方法前缀，例如 "POST /users" 对应用户注册接口，"/messages/chatgroups" 对应发送群聊消息接口。
+func NewRateLimiter(opts RateLimiterOptions) *RateLimiter {
+	return core.NewRateLimiter(opts)
+}
+
+// DefaultRateLimits 获取环信默认的接口限流规则
+func DefaultRateLimits() map[string]RateLimit {
+	return core.DefaultRateLimits()
+}
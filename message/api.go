@@ -1,20 +1,119 @@
 package message
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/dobyte/easemob-im-server-sdk/internal/core"
-	"reflect"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
 )
 
 const (
-	fileUrlFormat      = "%s/chatfiles/%s"
-	sendPrivateMsgUri  = "/messages/users"
-	sendGroupMsgUri    = "/messages/chatgroups"
-	sendChatroomMsgUri = "/messages/chatrooms"
+	fileUrlFormat         = "%s/chatfiles/%s"
+	sendPrivateMsgUri     = "/messages/users"
+	sendGroupMsgUri       = "/messages/chatgroups"
+	sendChatroomMsgUri    = "/messages/chatrooms"
+	recallMsgUri          = "/messages/msg_recall"
+	fetchRoamingMsgsUri   = "/users/%s/roaming_messages?%s"
+	deleteChatRoamingUri  = "/rest/message/roaming/chat/user/%s/time?%s"
+	deleteGroupRoamingUri = "/rest/message/roaming/group/user/%s/time?%s"
+	broadcastUsersUri     = "/messages/users/broadcast"
+	broadcastChatroomsUri = "/messages/chatrooms/broadcast"
 )
 
+const defaultBatchConcurrency = 4
+
+// 各目标单次请求允许的最大接收方数量
+var batchSizes = map[Target]int{
+	TargetUser:     600,
+	TargetGroup:    3,
+	TargetChatroom: 10,
+	TargetThread:   1,
+}
+
 type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
+	// Send 发送消息
+	// 根据消息的目标类型发送单聊、群聊或聊天室消息，支持文本、图片、语音、视频、文件、位置、透传和自定义消息。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送消息
+	Send(msg *Message) (map[string]*SendResult, error)
+
+	// SendUsers 发送单聊消息
+	// 给一个或多个用户发送消息，消息的接收方为指定的用户名。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送消息
+	SendUsers(msg *Message, usernames ...string) (map[string]*SendResult, error)
+
+	// SendGroup 发送群聊消息
+	// 给一个或多个群组发送消息，消息的接收方为指定的群组ID。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送消息
+	SendGroup(msg *Message, ids ...string) (map[string]*SendResult, error)
+
+	// SendChatroom 发送聊天室消息
+	// 给一个或多个聊天室发送消息，消息的接收方为指定的聊天室ID。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送消息
+	SendChatroom(msg *Message, ids ...string) (map[string]*SendResult, error)
+
+	// SendThread 发送子区消息
+	// 向群组子区（Thread）发送消息，子区ID为创建子区时返回的ID，返回消息ID。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送消息
+	SendThread(msg *Message, threadID string) (string, error)
+
+	// FetchRoamingMessages 分页拉取会话的漫游消息
+	// 拉取用户与指定用户、群组或聊天室之间的会话在服务端保存的漫游消息，消息按拉取方向排序并解析为消息。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#拉取漫游消息
+	FetchRoamingMessages(arg FetchRoamingMessagesArg) (*FetchRoamingMessagesRet, error)
+
+	// RoamingMessages 遍历会话的漫游消息
+	// 返回按游标自动翻页的迭代器，参数与 FetchRoamingMessages 相同，Limit 为每页拉取的消息数量。
+	RoamingMessages(arg FetchRoamingMessagesArg) *RoamingIterator
+
+	// DeleteRoamingMessages 单向删除会话的漫游消息
+	// 删除用户与指定用户或群组之间的会话在指定时间及之前的漫游消息，仅对该用户生效，会话对端仍可拉取这些消息。
+	// target 取值为 TargetUser 或 TargetGroup，before 为零值时删除当前时间之前的漫游消息。
+	// 清空用户全部会话的漫游消息请使用 user.API.DeleteRoamingMessages。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#单向删除会话的漫游消息
+	DeleteRoamingMessages(username, peer string, target Target, before time.Time) error
+
+	// BatchSend 分批发送消息
+	// 将接收方按接口限制自动拆分（单聊每批 600 个用户，群聊每批 3 个群组，聊天室每批 10 个聊天室），并以不超过 concurrency 个并发请求发送，concurrency 小于等于 0 时默认为 4。
+	// 重复的接收方只发送一次；消息本身无效时返回错误，单批发送失败时该批接收方的错误记录在返回结果的 Errors 中。
+	// 绑定的上下文结束后不再发送剩余批次，这些批次的接收方以上下文的错误记录在 Errors 中。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送消息
+	BatchSend(msg *Message, concurrency int) (*BatchSendResult, error)
+
+	// BroadcastUsers 向所有用户发送广播消息
+	// 向应用内所有在线用户发送广播消息，消息的目标及接收方会被忽略，发送方为空时默认为 admin，返回广播ID。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送全局广播消息
+	BroadcastUsers(msg *Message) (string, error)
+
+	// BroadcastChatrooms 向所有聊天室发送广播消息
+	// 向应用内所有活跃聊天室发送广播消息，消息的目标及接收方会被忽略，发送方为空时默认为 admin，返回广播ID。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#发送聊天室全局广播消息
+	BroadcastChatrooms(msg *Message) (string, error)
+
+	// Recall 撤回消息
+	// 撤回发送的单聊、群聊或聊天室消息，默认受控制台配置的撤回时限约束，设置 Force 后可撤回超过时限的消息。
+	// 撤回失败不会返回错误，请通过返回结果的 Recalled 与 Reason 判断。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#消息撤回
+	Recall(arg RecallArg) (*RecallResult, error)
 }
 
 type api struct {
@@ -25,159 +124,437 @@ func NewAPI(client core.Client) API {
 	return &api{client: client}
 }
 
-// Send 发送单聊消息
-func (a *api) Send(msg *Message) (map[string]string, error) {
-	if msg.err != nil {
-		return nil, msg.err
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
+// Send 发送消息
+func (a *api) Send(msg *Message) (map[string]*SendResult, error) {
+	uri, req, err := a.buildSendReq(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.send(uri, req)
+}
+
+// BatchSend 分批发送消息
+func (a *api) BatchSend(msg *Message, concurrency int) (*BatchSendResult, error) {
+	uri, req, err := a.buildSendReq(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.To) == 0 {
+		return nil, errors.New("the receivers of message is not set")
+	}
+
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	chunks := chunkReceivers(req.To, batchSizes[msg.target])
+	if concurrency > len(chunks) {
+		concurrency = len(chunks)
 	}
 
 	var (
-		buf []byte
-		err error
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ch  = make(chan []string)
+		ret = &BatchSendResult{
+			Results: make(map[string]*SendResult, len(req.To)),
+			Errors:  make(map[string]error),
+		}
 	)
-	switch msg.msgType {
-	case txt:
-		buf, err = toTxtBody(msg.msgBody.(*MsgTxt))
-	case image:
-		buf, err = toImageBody(a.client.BaseUrl(), msg.msgBody.(*MsgImage))
-	case audio:
-		buf, err = toAudioBody(a.client.BaseUrl(), msg.msgBody.(*MsgAudio))
-	case video:
-		buf, err = toVideoBody(a.client.BaseUrl(), msg.msgBody.(*MsgVideo))
-	case file:
-		buf, err = toFileBody(a.client.BaseUrl(), msg.msgBody.(*MsgFile))
-	case location:
-		buf, err = toLocationBody(msg.msgBody.(*MsgLocation))
-	case cmd:
-		buf, err = toCMDBody(msg.msgBody.(*MsgCMD))
-	case custom:
-		buf, err = toCustomBody(msg.msgBody.(*MsgCustom))
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for receivers := range ch {
+				r := *req
+				r.To = receivers
+				results, err := a.send(uri, &r)
+
+				mu.Lock()
+				if err != nil {
+					for _, receiver := range receivers {
+						ret.Errors[receiver] = err
+					}
+				} else {
+					for receiver, result := range results {
+						ret.Results[receiver] = result
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	ctx := a.client.Context()
+	rest := feedChunks(ctx, ch, chunks)
+	close(ch)
+	wg.Wait()
+
+	for _, receivers := range rest {
+		for _, receiver := range receivers {
+			ret.Errors[receiver] = ctx.Err()
+		}
 	}
+
+	return ret, nil
+}
+
+// BroadcastUsers 向所有用户发送广播消息
+func (a *api) BroadcastUsers(msg *Message) (string, error) {
+	req, err := a.buildBroadcastReq(msg)
+	if err != nil {
+		return "", err
+	}
+	req.TargetType = "users"
+
+	return a.broadcast(broadcastUsersUri, req)
+}
+
+// BroadcastChatrooms 向所有聊天室发送广播消息
+func (a *api) BroadcastChatrooms(msg *Message) (string, error) {
+	req, err := a.buildBroadcastReq(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return a.broadcast(broadcastChatroomsUri, req)
+}
+
+// 校验消息并构建广播消息请求，广播消息的消息体及扩展字段均为JSON对象
+func (a *api) buildBroadcastReq(msg *Message) (*broadcastReq, error) {
+	if msg.err != nil {
+		return nil, msg.err
+	}
+
+	if isNil(msg.msgBody) {
+		return nil, errors.New("the body of message is not set")
+	}
+
+	if err := msg.msgBody.validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := encodeBodyObject(msg.msgBody, a.client.BaseUrl())
+	if err != nil {
+		return nil, err
+	}
+
+	req := &broadcastReq{From: msg.sender, Msg: body}
+	if req.From == "" {
+		req.From = "admin"
+	}
+
+	ext, err := msg.buildExt()
 	if err != nil {
 		return nil, err
 	}
 
+	if !isNil(ext) {
+		req.Ext = ext
+	}
+
+	return req, nil
+}
+
+// 发送广播消息请求
+func (a *api) broadcast(uri string, req *broadcastReq) (string, error) {
+	resp := &broadcastResp{}
+	if err := a.client.Post(uri, req, resp); err != nil {
+		return "", err
+	}
+
+	return resp.Data.ID.String(), nil
+}
+
+// 校验消息并构建发送消息请求
+func (a *api) buildSendReq(msg *Message) (string, *sendReq, error) {
+	if msg.err != nil {
+		return "", nil, msg.err
+	}
+
+	if isNil(msg.msgBody) {
+		return "", nil, errors.New("the body of message is not set")
+	}
+
+	if err := msg.msgBody.validate(); err != nil {
+		return "", nil, err
+	}
+
+	var uri string
+	switch msg.target {
+	case TargetUser:
+		uri = sendPrivateMsgUri
+	case TargetGroup:
+		uri = sendGroupMsgUri
+	case TargetChatroom:
+		uri = sendChatroomMsgUri
+	case TargetThread:
+		uri = sendGroupMsgUri
+	default:
+		return "", nil, fmt.Errorf("invalid message target %d", msg.target)
+	}
+
+	buf, err := msg.msgBody.encode(a.client.BaseUrl())
+	if err != nil {
+		return "", nil, err
+	}
+
 	req := &sendReq{
 		From:       msg.sender,
 		To:         msg.receivers,
-		Type:       msg.msgType,
+		Type:       msg.msgBody.msgType(),
 		Body:       string(buf),
 		SyncDevice: msg.syncDevice,
+		IsThread:   msg.target == TargetThread,
 	}
-	resp := &sendResp{}
 
 	if msg.onlyOnline {
 		req.RouteType = "ROUTE_ONLINE"
 	}
 
-	if msg.ext != nil && !reflect.ValueOf(msg.ext).IsNil() {
-		buf, err := json.Marshal(msg.ext)
+	ext, err := msg.buildExt()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !isNil(ext) {
+		buf, err := json.Marshal(ext)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		req.Ext = string(buf)
 	}
 
-	var uri string
-	switch msg.target {
-	case TargetUser:
-		uri = sendPrivateMsgUri
-	case TargetGroup:
-		uri = sendGroupMsgUri
-	case TargetChatroom:
-		uri = sendChatroomMsgUri
-	}
+	return uri, req, nil
+}
 
-	if err = a.client.Post(uri, req, resp); err != nil {
+// 发送消息请求
+func (a *api) send(uri string, req *sendReq) (map[string]*SendResult, error) {
+	resp := &sendResp{}
+	if err := a.client.Post(uri, req, resp); err != nil {
 		return nil, err
 	}
 
-	return resp.Data, nil
+	ret := make(map[string]*SendResult, len(resp.Data))
+	for receiver, msgID := range resp.Data {
+		ret[receiver] = &SendResult{Receiver: receiver, MsgID: msgID}
+	}
+
+	return ret, nil
+}
+
+// SendUsers 发送单聊消息
+func (a *api) SendUsers(msg *Message, usernames ...string) (map[string]*SendResult, error) {
+	return a.sendTo(msg, TargetUser, usernames)
 }
 
-func toTxtBody(msg *MsgTxt) ([]byte, error) {
-	return json.Marshal(msg)
+// SendGroup 发送群聊消息
+func (a *api) SendGroup(msg *Message, ids ...string) (map[string]*SendResult, error) {
+	return a.sendTo(msg, TargetGroup, ids)
 }
 
-func toImageBody(baseUrl string, msg *MsgImage) ([]byte, error) {
-	size, err := json.Marshal(struct {
-		Width  int `json:"width"`
-		Height int `json:"height"`
-	}{
-		Width:  msg.Width,
-		Height: msg.Height,
-	})
+// SendChatroom 发送聊天室消息
+func (a *api) SendChatroom(msg *Message, ids ...string) (map[string]*SendResult, error) {
+	return a.sendTo(msg, TargetChatroom, ids)
+}
+
+// SendThread 发送子区消息
+func (a *api) SendThread(msg *Message, threadID string) (string, error) {
+	if threadID == "" {
+		return "", errors.New("the id of thread is not set")
+	}
+
+	ret, err := a.sendTo(msg, TargetThread, []string{threadID})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return json.Marshal(&msgImageBody{
-		Filename: msg.Filename,
-		Secret:   msg.Secret,
-		Size:     string(size),
-		Url:      fmt.Sprintf(fileUrlFormat, baseUrl, msg.UUID),
-	})
-}
+	if result, ok := ret[threadID]; ok {
+		return result.MsgID, nil
+	}
 
-func toAudioBody(baseUrl string, msg *MsgAudio) ([]byte, error) {
-	return json.Marshal(&msgAudioBody{
-		Filename: msg.Filename,
-		Secret:   msg.Secret,
-		Length:   msg.Length,
-		Url:      fmt.Sprintf(fileUrlFormat, baseUrl, msg.UUID),
-	})
+	return "", fmt.Errorf("the message id of thread %s is not returned", threadID)
 }
 
-func toVideoBody(baseUrl string, msg *MsgVideo) ([]byte, error) {
-	return json.Marshal(&msgVideoBody{
-		Thumb:       fmt.Sprintf(fileUrlFormat, baseUrl, msg.ThumbUUID),
-		Secret:      msg.VideoSecret,
-		Length:      msg.VideoLength,
-		FileLength:  msg.VideoSize,
-		ThumbSecret: msg.ThumbSecret,
-		Url:         fmt.Sprintf(fileUrlFormat, baseUrl, msg.VideoUUID),
-	})
+// FetchRoamingMessages 分页拉取会话的漫游消息
+func (a *api) FetchRoamingMessages(arg FetchRoamingMessagesArg) (*FetchRoamingMessagesRet, error) {
+	chatType, err := arg.Target.ChatType()
+	if err != nil {
+		return nil, err
+	}
+
+	query := new(url.URL).Query()
+	query.Set("to", arg.Peer)
+	query.Set("chat_type", chatType)
+	if !arg.StartTime.IsZero() {
+		query.Set("start_time", strconv.FormatInt(arg.StartTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if !arg.EndTime.IsZero() {
+		query.Set("end_time", strconv.FormatInt(arg.EndTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if arg.Direction != "" {
+		query.Set("direction", string(arg.Direction))
+	}
+	if arg.Limit > 0 {
+		query.Set("limit", strconv.Itoa(arg.Limit))
+	}
+	if arg.Cursor != "" {
+		query.Set("cursor", arg.Cursor)
+	}
+
+	resp := &fetchRoamingMessagesResp{}
+	if err = a.client.Get(fmt.Sprintf(fetchRoamingMsgsUri, arg.Username, query.Encode()), nil, resp); err != nil {
+		return nil, err
+	}
+
+	ret := &FetchRoamingMessagesRet{
+		List:    make([]*RoamingMessage, 0, len(resp.Data.Messages)),
+		HasMore: resp.Data.Cursor != "",
+		Cursor:  resp.Data.Cursor,
+	}
+	for _, item := range resp.Data.Messages {
+		msg, err := item.Payload.Message(arg.Target)
+		if err != nil {
+			return nil, fmt.Errorf("decode roaming message %s: %w", item.MsgID, err)
+		}
+
+		ret.List = append(ret.List, &RoamingMessage{MsgID: item.MsgID, Timestamp: item.Timestamp, Message: msg})
+	}
+
+	return ret, nil
 }
 
-func toFileBody(baseUrl string, msg *MsgFile) ([]byte, error) {
-	return json.Marshal(&msgFileBody{
-		Filename: msg.Filename,
-		Secret:   msg.Secret,
-		Url:      fmt.Sprintf(fileUrlFormat, baseUrl, msg.UUID),
-	})
+// RoamingMessages 遍历会话的漫游消息
+func (a *api) RoamingMessages(arg FetchRoamingMessagesArg) *RoamingIterator {
+	return &RoamingIterator{api: a, arg: arg}
 }
 
-func toLocationBody(msg *MsgLocation) ([]byte, error) {
-	return json.Marshal(msg)
+// DeleteRoamingMessages 单向删除会话的漫游消息
+func (a *api) DeleteRoamingMessages(username, peer string, target Target, before time.Time) error {
+	if before.IsZero() {
+		before = time.Now()
+	}
+
+	query := new(url.URL).Query()
+	query.Set("delTime", strconv.FormatInt(before.UnixNano()/int64(time.Millisecond), 10))
+
+	var uri string
+	switch target {
+	case TargetUser:
+		query.Set("userId", peer)
+		uri = fmt.Sprintf(deleteChatRoamingUri, username, query.Encode())
+	case TargetGroup:
+		query.Set("groupId", peer)
+		uri = fmt.Sprintf(deleteGroupRoamingUri, username, query.Encode())
+	default:
+		return fmt.Errorf("invalid message target %d, only users and groups support roaming message deletion", target)
+	}
+
+	return a.client.Delete(uri, nil, nil)
 }
 
-func toCMDBody(msg *MsgCMD) ([]byte, error) {
-	return json.Marshal(msg)
+// 按指定目标发送消息，不修改调用方的消息
+func (a *api) sendTo(msg *Message, target Target, receivers []string) (map[string]*SendResult, error) {
+	if len(receivers) == 0 {
+		return nil, errors.New("the receivers of message is not set")
+	}
+
+	m := *msg
+	m.target = target
+	m.receivers = receivers
+
+	return a.Send(&m)
 }
 
-func toCustomBody(msg *MsgCustom) ([]byte, error) {
-	var (
-		buf []byte
-		ext []byte
-		err error
-	)
+// Recall 撤回消息
+func (a *api) Recall(arg RecallArg) (*RecallResult, error) {
+	if arg.MsgID == "" {
+		return nil, errors.New("the id of message is not set")
+	}
 
-	if len(msg.CustomExts) > 0 {
-		if buf, err = json.Marshal(msg.CustomExts); err != nil {
-			return nil, err
+	chatType, err := arg.Target.ChatType()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &recallReq{MsgID: arg.MsgID, To: arg.To, ChatType: chatType, From: arg.From, Force: arg.Force}
+
+	if req.From == "" {
+		req.From = "admin"
+	}
+
+	resp := &recallResp{}
+	if err := a.client.Post(recallMsgUri, req, resp); err != nil {
+		return nil, err
+	}
+
+	data := &resp.Data.recallData
+	for _, item := range resp.Data.Msgs {
+		if item.MsgID == arg.MsgID {
+			data = item
+			break
+		}
+	}
+
+	ret := &RecallResult{
+		MsgID:    arg.MsgID,
+		To:       arg.To,
+		From:     req.From,
+		ChatType: req.ChatType,
+		Recalled: data.Recalled == "yes",
+	}
+	if !ret.Recalled {
+		ret.Reason = data.RecallFailed
+		if ret.Reason == "" {
+			ret.Reason = data.Recalled
 		}
 	}
 
-	if msg.Ext != nil && !reflect.ValueOf(msg.Ext).IsNil() {
-		if ext, err = json.Marshal(msg.Ext); err != nil {
-			return nil, err
+	return ret, nil
+}
+
+// 按批次大小拆分去重后的接收方
+func chunkReceivers(receivers []string, size int) [][]string {
+	seen := make(map[string]struct{}, len(receivers))
+	unique := make([]string, 0, len(receivers))
+	for _, receiver := range receivers {
+		if _, ok := seen[receiver]; ok {
+			continue
+		}
+		seen[receiver] = struct{}{}
+		unique = append(unique, receiver)
+	}
+
+	chunks := make([][]string, 0, (len(unique)+size-1)/size)
+	for size < len(unique) {
+		unique, chunks = unique[size:], append(chunks, unique[:size:size])
+	}
+
+	return append(chunks, unique)
+}
+
+// 依次将批次交给发送协程，上下文结束时停止并返回未发送的批次
+func feedChunks(ctx context.Context, ch chan<- []string, chunks [][]string) [][]string {
+	for i, receivers := range chunks {
+		if ctx.Err() != nil {
+			return chunks[i:]
+		}
+
+		select {
+		case ch <- receivers:
+		case <-ctx.Done():
+			return chunks[i:]
 		}
 	}
 
-	return json.Marshal(&msgCustomBody{
-		CustomEvent: msg.CustomEvent,
-		CustomExts:  string(buf),
-		From:        msg.From,
-		Ext:         string(ext),
-	})
+	return nil
 }
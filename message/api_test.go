@@ -0,0 +1,159 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// 创建请求指向测试服务的消息接口
+func newTestAPI(t *testing.T, handler http.HandlerFunc) API {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := core.NewClient(&core.Options{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+		AppKey: "org#app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewAPI(client)
+}
+
+func TestAPI_Recall(t *testing.T) {
+	var reqs []map[string]interface{}
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		req := make(map[string]interface{})
+		if err := json.Unmarshal(buf, &req); err != nil {
+			t.Error(err)
+		}
+		reqs = append(reqs, req)
+
+		w.Write([]byte(`{"data":{"msgs":[{"msg_id":"1","recalled":"not_found msg","recall_failed":"exceed recall time limit"}]}}`))
+	})
+
+	ret, err := api.Recall(RecallArg{MsgID: "1", Target: TargetGroup, To: "g1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret.Recalled || ret.Reason != "exceed recall time limit" || ret.From != "admin" || ret.ChatType != "groupchat" {
+		t.Fatalf("unexpected result %+v", ret)
+	}
+
+	if _, err = api.Recall(RecallArg{MsgID: "1", Target: TargetUser, To: "u2", From: "u1", Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reqs) != 2 || reqs[0]["force"] != false || reqs[1]["force"] != true || reqs[1]["from"] != "u1" {
+		t.Fatalf("unexpected requests %v", reqs)
+	}
+
+	if _, err = api.Recall(RecallArg{Target: TargetUser, To: "u2"}); err == nil {
+		t.Fatal("missing message id should be rejected")
+	}
+}
+
+func TestChunkReceivers(t *testing.T) {
+	cases := []struct {
+		receivers []string
+		size      int
+		want      [][]string
+	}{
+		{[]string{"a", "b", "c", "d"}, 2, [][]string{{"a", "b"}, {"c", "d"}}},
+		{[]string{"a", "b", "c", "d", "e"}, 2, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{[]string{"a", "b", "a", "c", "b", "d"}, 3, [][]string{{"a", "b", "c"}, {"d"}}},
+		{[]string{"a", "a", "a"}, 1, [][]string{{"a"}}},
+		{[]string{"a"}, 600, [][]string{{"a"}}},
+	}
+
+	for _, c := range cases {
+		got := chunkReceivers(c.receivers, c.size)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("chunkReceivers(%v, %d) = %v, want %v", c.receivers, c.size, got, c.want)
+		}
+	}
+
+	chunks := chunkReceivers([]string{"a", "b", "c"}, 2)
+	chunks[0] = append(chunks[0], "x")
+	if chunks[1][0] != "c" {
+		t.Fatal("appending to a chunk should not overwrite the next chunk")
+	}
+}
+
+// 记录发送请求的客户端，不受上下文影响，用于观察上下文结束后是否仍有请求
+type recordClient struct {
+	core.Client
+	ctx   context.Context
+	calls int32
+	post  func(calls int32)
+}
+
+func (c *recordClient) BaseUrl() string {
+	return "http://localhost/org/app"
+}
+
+func (c *recordClient) Context() context.Context {
+	return c.ctx
+}
+
+func (c *recordClient) Post(uri string, data interface{}, resp interface{}) error {
+	c.post(atomic.AddInt32(&c.calls, 1))
+
+	req := data.(*sendReq)
+	ret := resp.(*sendResp)
+	ret.Data = make(map[string]string, len(req.To))
+	for _, receiver := range req.To {
+		ret.Data[receiver] = "msg-" + receiver
+	}
+
+	return nil
+}
+
+func TestAPI_BatchSendCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &recordClient{ctx: ctx, post: func(calls int32) {
+		if calls == 1 {
+			cancel()
+		}
+	}}
+
+	msg := NewMessage(TargetGroup)
+	msg.SetBody(MsgTxt{Msg: "hello"})
+	for i := 0; i < 30; i++ {
+		msg.AddReceivers(strconv.Itoa(i))
+	}
+
+	ret, err := NewAPI(client).BatchSend(msg, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := atomic.LoadInt32(&client.calls); n != 1 {
+		t.Fatalf("expected no request after the context is cancelled, got %d requests", n)
+	}
+
+	if len(ret.Results) != 3 || len(ret.Errors) != 27 {
+		t.Fatalf("expected 3 results and 27 errors, got %d and %d", len(ret.Results), len(ret.Errors))
+	}
+
+	for receiver, err := range ret.Errors {
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("unexpected error for %s: %v", receiver, err)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+const maxCustomExts = 16
+
+var customEventRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_/.]{1,32}$`)
+
+// 各类消息体中被编码为JSON字符串的嵌套对象字段
+var nestedObjectKeys = map[string][]string{
+	image:  {"size"},
+	custom: {"customExts", "ext"},
+}
+
+// Body 消息体
+// 由 MsgTxt、MsgImage、MsgAudio、MsgVideo、MsgFile、MsgLocation、MsgCMD、MsgCustom 实现，值与指针均可作为消息体。
+type Body interface {
+	// 获取消息类型
+	msgType() string
+	// 校验消息体
+	validate() error
+	// 编码为发送消息接口的消息体
+	encode(baseUrl string) ([]byte, error)
+}
+
+func (b MsgTxt) msgType() string {
+	return txt
+}
+
+func (b MsgTxt) validate() error {
+	if b.Msg == "" {
+		return errors.New("invalid txt msg: msg is required")
+	}
+
+	return nil
+}
+
+func (b MsgTxt) encode(baseUrl string) ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b MsgImage) msgType() string {
+	return image
+}
+
+func (b MsgImage) validate() error {
+	if b.UUID == "" {
+		return errors.New("invalid img msg: uuid is required")
+	}
+
+	return nil
+}
+
+func (b MsgImage) encode(baseUrl string) ([]byte, error) {
+	size, err := json.Marshal(struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}{
+		Width:  b.Width,
+		Height: b.Height,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&msgImageBody{
+		Filename: b.Filename,
+		Secret:   b.Secret,
+		Size:     string(size),
+		Url:      fmt.Sprintf(fileUrlFormat, baseUrl, b.UUID),
+	})
+}
+
+func (b MsgAudio) msgType() string {
+	return audio
+}
+
+func (b MsgAudio) validate() error {
+	if b.UUID == "" {
+		return errors.New("invalid audio msg: uuid is required")
+	}
+
+	return nil
+}
+
+func (b MsgAudio) encode(baseUrl string) ([]byte, error) {
+	return json.Marshal(&msgAudioBody{
+		Filename: b.Filename,
+		Secret:   b.Secret,
+		Length:   b.Length,
+		Url:      fmt.Sprintf(fileUrlFormat, baseUrl, b.UUID),
+	})
+}
+
+func (b MsgVideo) msgType() string {
+	return video
+}
+
+func (b MsgVideo) validate() error {
+	if b.VideoUUID == "" {
+		return errors.New("invalid video msg: video uuid is required")
+	}
+
+	return nil
+}
+
+func (b MsgVideo) encode(baseUrl string) ([]byte, error) {
+	return json.Marshal(&msgVideoBody{
+		Thumb:       fmt.Sprintf(fileUrlFormat, baseUrl, b.ThumbUUID),
+		Secret:      b.VideoSecret,
+		Length:      b.VideoLength,
+		FileLength:  b.VideoSize,
+		ThumbSecret: b.ThumbSecret,
+		Url:         fmt.Sprintf(fileUrlFormat, baseUrl, b.VideoUUID),
+	})
+}
+
+func (b MsgFile) msgType() string {
+	return file
+}
+
+func (b MsgFile) validate() error {
+	if b.UUID == "" {
+		return errors.New("invalid file msg: uuid is required")
+	}
+
+	return nil
+}
+
+func (b MsgFile) encode(baseUrl string) ([]byte, error) {
+	return json.Marshal(&msgFileBody{
+		Filename: b.Filename,
+		Secret:   b.Secret,
+		Url:      fmt.Sprintf(fileUrlFormat, baseUrl, b.UUID),
+	})
+}
+
+func (b MsgLocation) msgType() string {
+	return location
+}
+
+func (b MsgLocation) validate() error {
+	if b.Lat < -90 || b.Lat > 90 || b.Lng < -180 || b.Lng > 180 {
+		return fmt.Errorf("invalid loc msg: lat %v or lng %v is out of range", b.Lat, b.Lng)
+	}
+
+	return nil
+}
+
+func (b MsgLocation) encode(baseUrl string) ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b MsgCMD) msgType() string {
+	return cmd
+}
+
+func (b MsgCMD) validate() error {
+	if b.Action == "" {
+		return errors.New("invalid cmd msg: action is required")
+	}
+
+	return nil
+}
+
+func (b MsgCMD) encode(baseUrl string) ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b MsgCustom) msgType() string {
+	return custom
+}
+
+func (b MsgCustom) validate() error {
+	if b.CustomEvent != "" && !customEventRegexp.MatchString(b.CustomEvent) {
+		return fmt.Errorf("invalid custom msg: customEvent %q should match [a-zA-Z0-9-_/\\.]{1,32}", b.CustomEvent)
+	}
+
+	if len(b.CustomExts) > maxCustomExts {
+		return fmt.Errorf("invalid custom msg: customExts should contain at most %d entries, got %d", maxCustomExts, len(b.CustomExts))
+	}
+
+	return nil
+}
+
+func (b MsgCustom) encode(baseUrl string) ([]byte, error) {
+	var (
+		buf []byte
+		ext []byte
+		err error
+	)
+
+	if len(b.CustomExts) > 0 {
+		if buf, err = json.Marshal(b.CustomExts); err != nil {
+			return nil, err
+		}
+	}
+
+	if !isNil(b.Ext) {
+		if ext, err = json.Marshal(b.Ext); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(&msgCustomBody{
+		CustomEvent: b.CustomEvent,
+		CustomExts:  string(buf),
+		From:        b.From,
+		Ext:         string(ext),
+	})
+}
+
+// 将消息体编码为JSON对象，被编码为JSON字符串的嵌套对象（例如图片尺寸、自定义事件属性）还原为JSON对象
+func encodeBodyObject(body Body, baseUrl string) (map[string]interface{}, error) {
+	buf, err := body.encode(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := make(map[string]interface{})
+	if err = json.Unmarshal(buf, &obj); err != nil {
+		return nil, err
+	}
+
+	for _, key := range nestedObjectKeys[body.msgType()] {
+		if s, ok := obj[key].(string); ok && s != "" {
+			var v interface{}
+			if err = json.Unmarshal([]byte(s), &v); err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+	}
+	obj["type"] = body.msgType()
+
+	return obj, nil
+}
+
+// 判断值是否为空，包括值为nil的指针、映射、切片等
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
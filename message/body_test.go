@@ -0,0 +1,54 @@
+package message
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMsgCustom_Encode(t *testing.T) {
+	buf, err := MsgCustom{CustomEvent: "gift", CustomExts: map[string]string{"id": "1"}, Ext: map[string]string{"k": "v"}}.encode("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := make(map[string]string)
+	if err = json.Unmarshal(buf, &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["customEvent"] != "gift" || body["customExts"] != `{"id":"1"}` || body["ext"] != `{"k":"v"}` {
+		t.Fatalf("unexpected body %s", buf)
+	}
+
+	if _, ok := body["CustomExts"]; ok {
+		t.Fatalf("unexpected key CustomExts in %s", buf)
+	}
+}
+
+func TestEncodeBodyObject(t *testing.T) {
+	cases := []struct {
+		body Body
+		want string
+	}{
+		{MsgTxt{Msg: `{"a":1}`}, `{"msg":"{\"a\":1}","type":"txt"}`},
+		{MsgCMD{Action: "{action}"}, `{"action":"{action}","type":"cmd"}`},
+		{MsgImage{UUID: "uuid", Filename: "{a}.png", Width: 480, Height: 720}, `{"filename":"{a}.png","secret":"","size":{"height":720,"width":480},"type":"img","url":"http://localhost/chatfiles/uuid"}`},
+		{MsgCustom{CustomEvent: "gift", CustomExts: map[string]string{"id": "{1}"}, Ext: "{raw}"}, `{"customEvent":"gift","customExts":{"id":"{1}"},"ext":"{raw}","from":"","type":"custom"}`},
+	}
+
+	for _, c := range cases {
+		obj, err := encodeBodyObject(c.body, "http://localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(buf) != c.want {
+			t.Errorf("encodeBodyObject(%+v) = %s, want %s", c.body, buf, c.want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Builder 消息构建器
+// 构建器不可变，每次设置都返回新的构建器，因此可以安全地复用公共部分派生多条消息，例如：
+// message.NewText("hi").From("admin").ToGroup(id).SyncDevice().Build()
+type Builder struct {
+	body       Body
+	sender     string
+	targets    []Target
+	receivers  []string
+	syncDevice bool
+	onlyOnline bool
+	ext        interface{}
+	push       *PushOptions
+}
+
+// BuildError 消息构建错误，包含构建消息时发现的全部问题
+type BuildError struct {
+	Errs []error
+}
+
+// Error 获取错误信息
+func (e *BuildError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "invalid message: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap 获取全部问题，Go 1.20 及以上版本的 errors.Is 与 errors.As 将逐一匹配
+func (e *BuildError) Unwrap() []error {
+	return e.Errs
+}
+
+// Is 判断是否包含指定错误，使 Go 1.20 以下版本的 errors.Is 也能匹配其中的问题
+func (e *BuildError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As 查找第一个可赋值给 target 的问题，使 Go 1.20 以下版本的 errors.As 也能匹配其中的问题
+func (e *BuildError) As(target interface{}) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewBuilder 使用指定的消息体创建消息构建器
+func NewBuilder(body Body) Builder {
+	return Builder{body: body}
+}
+
+// NewText 创建文本消息构建器
+func NewText(msg string) Builder {
+	return NewBuilder(MsgTxt{Msg: msg})
+}
+
+// NewImage 创建图片消息构建器
+func NewImage(img MsgImage) Builder {
+	return NewBuilder(img)
+}
+
+// NewAudio 创建语音消息构建器
+func NewAudio(audio MsgAudio) Builder {
+	return NewBuilder(audio)
+}
+
+// NewVideo 创建视频消息构建器
+func NewVideo(video MsgVideo) Builder {
+	return NewBuilder(video)
+}
+
+// NewFile 创建文件消息构建器
+func NewFile(file MsgFile) Builder {
+	return NewBuilder(file)
+}
+
+// NewLocation 创建位置消息构建器
+func NewLocation(lat, lng float64, addr string) Builder {
+	return NewBuilder(MsgLocation{Lat: lat, Lng: lng, Addr: addr})
+}
+
+// NewCMD 创建透传消息构建器
+func NewCMD(action string) Builder {
+	return NewBuilder(MsgCMD{Action: action})
+}
+
+// NewCustom 创建自定义消息构建器
+func NewCustom(custom MsgCustom) Builder {
+	return NewBuilder(custom)
+}
+
+// From 设置发送方
+func (b Builder) From(sender string) Builder {
+	b.sender = sender
+	return b
+}
+
+// ToUsers 设置接收消息的用户
+func (b Builder) ToUsers(usernames ...string) Builder {
+	return b.to(TargetUser, usernames)
+}
+
+// ToGroup 设置接收消息的群组
+func (b Builder) ToGroup(ids ...string) Builder {
+	return b.to(TargetGroup, ids)
+}
+
+// ToChatroom 设置接收消息的聊天室
+func (b Builder) ToChatroom(ids ...string) Builder {
+	return b.to(TargetChatroom, ids)
+}
+
+// ToThread 设置接收消息的群组子区
+func (b Builder) ToThread(ids ...string) Builder {
+	return b.to(TargetThread, ids)
+}
+
+// SyncDevice 设置消息发送成功后同步至发送方
+func (b Builder) SyncDevice() Builder {
+	b.syncDevice = true
+	return b
+}
+
+// OnlyOnline 设置只有接收方在线时，消息才能成功发送
+func (b Builder) OnlyOnline() Builder {
+	b.onlyOnline = true
+	return b
+}
+
+// Ext 设置消息扩展字段
+func (b Builder) Ext(ext interface{}) Builder {
+	b.ext = ext
+	return b
+}
+
+// Push 设置离线推送，发送时合并至消息扩展字段
+func (b Builder) Push(opts PushOptions) Builder {
+	b.push = &opts
+	return b
+}
+
+// Build 构建消息，返回的错误包含全部校验问题
+func (b Builder) Build() (*Message, error) {
+	var errs []error
+
+	if isNil(b.body) {
+		errs = append(errs, errors.New("body is not set"))
+	} else if err := b.body.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch len(b.targets) {
+	case 0:
+		errs = append(errs, errors.New("receivers is not set"))
+	case 1:
+		if len(b.receivers) == 0 {
+			errs = append(errs, errors.New("receivers is not set"))
+		}
+	default:
+		errs = append(errs, errors.New("receivers should be users, groups, chatrooms or threads, not a mix of them"))
+	}
+
+	if b.push != nil {
+		if err := b.push.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, receiver := range b.receivers {
+		if strings.TrimSpace(receiver) == "" {
+			errs = append(errs, fmt.Errorf("receiver at index %d is empty", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &BuildError{Errs: errs}
+	}
+
+	return &Message{
+		target:     b.targets[0],
+		sender:     b.sender,
+		receivers:  append([]string(nil), b.receivers...),
+		msgType:    b.body.msgType(),
+		msgBody:    b.body,
+		syncDevice: b.syncDevice,
+		onlyOnline: b.onlyOnline,
+		ext:        b.ext,
+		push:       b.push,
+	}, nil
+}
+
+// 追加接收方，复制切片以免与派生出该构建器的其他构建器共享底层数组
+func (b Builder) to(target Target, receivers []string) Builder {
+	b.targets = appendTarget(b.targets, target)
+	b.receivers = append(b.receivers[:len(b.receivers):len(b.receivers)], receivers...)
+	return b
+}
+
+// 追加不重复的消息目标
+func appendTarget(targets []Target, target Target) []Target {
+	for _, t := range targets {
+		if t == target {
+			return targets
+		}
+	}
+
+	return append(targets[:len(targets):len(targets)], target)
+}
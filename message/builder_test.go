@@ -0,0 +1,47 @@
+package message_test
+
+import (
+	"errors"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"io"
+	"testing"
+)
+
+func TestBuilder_Invalid(t *testing.T) {
+	_, err := message.NewText("").ToUsers("test1").ToGroup("188864710901761").Build()
+
+	var buildErr *message.BuildError
+	if !errors.As(err, &buildErr) || len(buildErr.Errs) != 2 {
+		t.Fatal(err)
+	}
+}
+
+type testError struct {
+	field string
+}
+
+func (e *testError) Error() string {
+	return e.field + " is invalid"
+}
+
+func TestBuildError_IsAs(t *testing.T) {
+	var err error = &message.BuildError{Errs: []error{io.EOF, &testError{field: "sender"}}}
+
+	if !errors.Is(err, io.EOF) {
+		t.Fatal("expected errors.Is to match a wrapped error")
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatal("expected errors.Is not to match an absent error")
+	}
+
+	var target *testError
+	if !errors.As(err, &target) || target.field != "sender" {
+		t.Fatalf("expected errors.As to find the wrapped error, got %v", target)
+	}
+
+	var buildErr *message.BuildError
+	if !errors.As(err, &buildErr) || len(buildErr.Errs) != 2 {
+		t.Fatal("expected errors.As to find the build error itself")
+	}
+}
@@ -0,0 +1,216 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type decodeData struct {
+	From       string          `json:"from"`
+	To         json.RawMessage `json:"to"`
+	Type       string          `json:"type"`
+	Body       json.RawMessage `json:"body"`
+	SyncDevice bool            `json:"sync_device"`
+	RouteType  string          `json:"routetype"`
+	Ext        json.RawMessage `json:"ext"`
+	ChatType   string          `json:"chat_type"`
+	IsThread   bool            `json:"is_thread"`
+}
+
+// Decode 解析消息
+// 将发送消息接口格式的消息（消息类型、JSON字符串形式的消息体及扩展字段）还原为消息，与发送消息互逆。
+// 消息体及扩展字段也可以是JSON对象；chat_type 为 groupchat 或 chatroom 时消息目标为群组（is_thread 为 true 时为子区）或聊天室，否则为用户。
+func Decode(data []byte) (*Message, error) {
+	v := &decodeData{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	msg := &Message{sender: v.From, syncDevice: v.SyncDevice, onlyOnline: v.RouteType == "ROUTE_ONLINE"}
+
+	switch strings.ToLower(v.ChatType) {
+	case "", "chat":
+		msg.target = TargetUser
+	case "groupchat":
+		msg.target = TargetGroup
+		if v.IsThread {
+			msg.target = TargetThread
+		}
+	case "chatroom":
+		msg.target = TargetChatroom
+	default:
+		return nil, fmt.Errorf("invalid chat type %q", v.ChatType)
+	}
+
+	if err := unmarshalReceivers(v.To, &msg.receivers); err != nil {
+		return nil, err
+	}
+
+	body := json.RawMessage{}
+	if err := unmarshalLoose(v.Body, &body); err != nil {
+		return nil, err
+	}
+
+	switch v.Type {
+	case txt, image, audio, video, file, location, cmd, custom:
+	default:
+		return nil, fmt.Errorf("invalid msg type %q", v.Type)
+	}
+
+	msgBody, err := decodeBody(v.Type, body)
+	if err != nil {
+		return nil, err
+	}
+	msg.msgType = v.Type
+	msg.msgBody = msgBody.(Body)
+
+	var ext map[string]interface{}
+	if err = unmarshalLoose(v.Ext, &ext); err != nil {
+		return nil, err
+	}
+	if ext != nil {
+		msg.ext = ext
+	}
+
+	return msg, nil
+}
+
+// 解析接收方，兼容单个接收方与接收方数组
+func unmarshalReceivers(data json.RawMessage, receivers *[]string) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if data[0] == '"' {
+		var receiver string
+		if err := json.Unmarshal(data, &receiver); err != nil {
+			return err
+		}
+		*receivers = []string{receiver}
+		return nil
+	}
+
+	if data[0] != '[' {
+		return errors.New("invalid receivers of message")
+	}
+
+	return json.Unmarshal(data, receivers)
+}
+
+// 按消息类型解析消息体，兼容字段以JSON字符串或JSON对象两种形式出现
+func decodeBody(msgType string, data []byte) (interface{}, error) {
+	switch msgType {
+	case txt:
+		body := &MsgTxt{}
+		return body, json.Unmarshal(data, body)
+	case image:
+		v := &msgImageData{}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+
+		size := &msgImageSize{}
+		if err := unmarshalLoose(v.Size, size); err != nil {
+			return nil, err
+		}
+
+		return &MsgImage{
+			Filename: v.Filename,
+			Secret:   v.Secret,
+			Width:    size.Width,
+			Height:   size.Height,
+			UUID:     uuidOf(v.Url),
+		}, nil
+	case audio:
+		v := &msgAudioBody{}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+
+		return &MsgAudio{Filename: v.Filename, Secret: v.Secret, Length: v.Length, UUID: uuidOf(v.Url)}, nil
+	case video:
+		v := &msgVideoBody{}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+
+		return &MsgVideo{
+			ThumbUUID:   uuidOf(v.Thumb),
+			ThumbSecret: v.ThumbSecret,
+			VideoLength: v.Length,
+			VideoSecret: v.Secret,
+			VideoSize:   v.FileLength,
+			VideoUUID:   uuidOf(v.Url),
+		}, nil
+	case file:
+		v := &msgFileBody{}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+
+		return &MsgFile{Filename: v.Filename, Secret: v.Secret, UUID: uuidOf(v.Url)}, nil
+	case location:
+		body := &MsgLocation{}
+		return body, json.Unmarshal(data, body)
+	case cmd:
+		body := &MsgCMD{}
+		return body, json.Unmarshal(data, body)
+	case custom:
+		v := &msgCustomData{}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, err
+		}
+
+		body := &MsgCustom{CustomEvent: v.CustomEvent, From: v.From}
+		if err := unmarshalLoose(v.CustomExts, &body.CustomExts); err != nil {
+			return nil, err
+		}
+		if err := unmarshalLoose(v.Ext, &body.Ext); err != nil {
+			return nil, err
+		}
+
+		return body, nil
+	default:
+		body := make(map[string]interface{})
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, fmt.Errorf("invalid %q message body: %w", msgType, err)
+		}
+
+		return body, nil
+	}
+}
+
+// 解析可能被编码为JSON字符串的JSON值，值为空、null或空字符串时忽略
+func unmarshalLoose(data json.RawMessage, v interface{}) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		if s = strings.TrimSpace(s); s == "" {
+			return nil
+		}
+		data = []byte(s)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// 从文件地址 https://{host}/{org_name}/{app_name}/chatfiles/{uuid} 中提取文件ID
+func uuidOf(url string) string {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		url = url[:i]
+	}
+
+	return url[strings.LastIndexByte(url, '/')+1:]
+}
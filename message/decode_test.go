@@ -0,0 +1,57 @@
+package message_test
+
+import (
+	"encoding/json"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	msg, err := message.Decode([]byte(`{"from":"test1","to":["test2"],"type":"img","body":"{\"filename\":\"test.png\",\"secret\":\"\",\"size\":\"{\\\"width\\\":480,\\\"height\\\":720}\",\"url\":\"https://a1.easemob.com/org/app/chatfiles/uuid\"}","ext":"{\"k\":\"v\"}"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, ok := msg.GetBody().(*message.MsgImage)
+	if !ok || body.UUID != "uuid" || body.Width != 480 || body.Height != 720 {
+		t.Fatalf("%+v", msg.GetBody())
+	}
+
+	if msg.GetSender() != "test1" || len(msg.GetReceivers()) != 1 || msg.GetReceivers()[0] != "test2" {
+		t.Fatalf("unexpected sender %q or receivers %v", msg.GetSender(), msg.GetReceivers())
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	const payload = `{"from":"test1","to":"test2","bodies":[{"type":"txt","msg":"hello"}],"ext":{"k":"v"}}`
+
+	quoted, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range []string{payload, string(quoted)} {
+		p, err := message.DecodePayload([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p == nil || p.From != "test1" || p.To != "test2" || p.Ext["k"] != "v" || len(p.Bodies) != 1 {
+			t.Fatalf("unexpected payload %+v", p)
+		}
+
+		if body, ok := p.Bodies[0].(*message.MsgTxt); !ok || body.Msg != "hello" {
+			t.Fatalf("unexpected body %+v", p.Bodies[0])
+		}
+	}
+
+	for _, data := range []string{"", "null", `""`} {
+		if p, err := message.DecodePayload([]byte(data)); err != nil || p != nil {
+			t.Fatalf("expected no payload for %q, got %+v %v", data, p, err)
+		}
+	}
+
+	if _, err = message.DecodePayload([]byte(`{"bodies":"invalid"}`)); err == nil {
+		t.Fatal("expected an error for an invalid payload")
+	}
+}
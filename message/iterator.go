@@ -0,0 +1,58 @@
+package message
+
+// RoamingIterator 漫游消息迭代器
+// 按游标自动翻页，用法如下：
+// it := api.RoamingMessages(arg)
+// for it.Next() { msg := it.Message() }
+// if err := it.Err(); err != nil { ... }
+type RoamingIterator struct {
+	api     *api
+	arg     FetchRoamingMessagesArg
+	list    []*RoamingMessage
+	current *RoamingMessage
+	done    bool
+	err     error
+}
+
+// Next 移动至下一条消息，没有更多消息或拉取失败时返回false
+func (it *RoamingIterator) Next() bool {
+	for len(it.list) == 0 {
+		if it.err != nil || it.done {
+			it.current = nil
+			return false
+		}
+
+		ret, err := it.api.FetchRoamingMessages(it.arg)
+		if err != nil {
+			it.err = err
+			it.current = nil
+			return false
+		}
+
+		// 游标未推进时停止，相同游标的请求只会返回同一页，继续拉取将导致无限请求
+		stalled := ret.Cursor == it.arg.Cursor
+
+		it.list = ret.List
+		it.arg.Cursor = ret.Cursor
+		it.done = !ret.HasMore || stalled
+	}
+
+	it.current, it.list = it.list[0], it.list[1:]
+
+	return true
+}
+
+// Message 获取当前消息
+func (it *RoamingIterator) Message() *RoamingMessage {
+	return it.current
+}
+
+// Cursor 获取下一页的游标，可用于中断后继续拉取
+func (it *RoamingIterator) Cursor() string {
+	return it.arg.Cursor
+}
+
+// Err 获取拉取过程中发生的错误
+func (it *RoamingIterator) Err() error {
+	return it.err
+}
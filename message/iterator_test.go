@@ -0,0 +1,127 @@
+package message
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRoamingIterator(t *testing.T) {
+	pages := map[string]struct {
+		ids    []string
+		cursor string
+	}{
+		"":   {[]string{"1", "2"}, "c1"},
+		"c1": {nil, "c2"},
+		"c2": {[]string{"3"}, "c3"},
+		"c3": {nil, "c3"},
+	}
+
+	var cursors []string
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("to") != "test2" || query.Get("chat_type") != "chat" || query.Get("direction") != "down" || query.Get("limit") != "2" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+
+		cursor := query.Get("cursor")
+		cursors = append(cursors, cursor)
+		if len(cursors) > len(pages) {
+			t.Errorf("too many requests %v", cursors)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		page := pages[cursor]
+		items := make([]string, 0, len(page.ids))
+		for _, id := range page.ids {
+			items = append(items, fmt.Sprintf(`{"msg_id":"%s","timestamp":1,"payload":{"from":"test1","to":"test2","bodies":[{"type":"txt","msg":"hello %s"}],"ext":{"k":"v"}}}`, id, id))
+		}
+		fmt.Fprintf(w, `{"data":{"messages":[%s],"cursor":"%s"}}`, strings.Join(items, ","), page.cursor)
+	})
+
+	it := api.RoamingMessages(FetchRoamingMessagesArg{
+		Username:  "test1",
+		Peer:      "test2",
+		Target:    TargetUser,
+		Direction: RoamingDirectionDown,
+		Limit:     2,
+	})
+
+	var ids []string
+	for it.Next() {
+		item := it.Message()
+		ids = append(ids, item.MsgID)
+
+		body, ok := item.Message.GetBody().(*MsgTxt)
+		if !ok || body.Msg != "hello "+item.MsgID || item.Message.GetSender() != "test1" || item.Message.GetReceivers()[0] != "test2" {
+			t.Fatalf("unexpected message %+v", item.Message)
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Fatalf("unexpected messages %v", ids)
+	}
+
+	if strings.Join(cursors, ",") != ",c1,c2,c3" {
+		t.Fatalf("unexpected cursors %v", cursors)
+	}
+
+	if it.Next() {
+		t.Fatal("exhausted iterator should not advance")
+	}
+}
+
+func TestRoamingIterator_StalledCursor(t *testing.T) {
+	var requests int
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 2 {
+			t.Errorf("too many requests with cursor %q", r.URL.Query().Get("cursor"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprintf(w, `{"data":{"messages":[{"msg_id":"%d","timestamp":1,"payload":{"from":"test1","to":"test2","bodies":[{"type":"txt","msg":"hello"}]}}],"cursor":"c1"}}`, requests)
+	})
+
+	it := api.RoamingMessages(FetchRoamingMessagesArg{Username: "test1", Peer: "test2", Target: TargetUser})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Message().MsgID)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(ids, ",") != "1,2" || requests != 2 {
+		t.Fatalf("expected to stop after the cursor repeated on a non-empty page, got messages %v in %d requests", ids, requests)
+	}
+}
+
+func TestPayload_Message(t *testing.T) {
+	payload := &Payload{From: "test1", To: "test2", Bodies: []interface{}{&MsgCMD{Action: "ping"}}}
+
+	msg, err := payload.Message(TargetGroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.GetTarget() != TargetGroup || msg.GetType() != cmd || msg.GetSender() != "test1" || msg.GetExt() != nil {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	if _, err = (&Payload{}).Message(TargetUser); err == nil {
+		t.Fatal("payload without bodies should be rejected")
+	}
+
+	if _, err = (&Payload{Bodies: []interface{}{map[string]interface{}{"type": "unknown"}}}).Message(TargetUser); err == nil {
+		t.Fatal("unknown body should be rejected")
+	}
+}
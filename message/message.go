@@ -2,6 +2,7 @@ package message
 
 import (
 	"errors"
+	"fmt"
 )
 
 type Target int
@@ -10,8 +11,24 @@ const (
 	TargetUser     Target = iota // 针对用户
 	TargetGroup                  // 针对群组
 	TargetChatroom               // 针对聊天室
+	TargetThread                 // 针对群组子区
 )
 
+// ChatType 获取消息目标对应的会话类型
+// 用户为 chat，群组及子区为 groupchat，聊天室为 chatroom。
+func (t Target) ChatType() (string, error) {
+	switch t {
+	case TargetUser:
+		return "chat", nil
+	case TargetGroup, TargetThread:
+		return "groupchat", nil
+	case TargetChatroom:
+		return "chatroom", nil
+	default:
+		return "", fmt.Errorf("invalid message target %d", t)
+	}
+}
+
 const (
 	txt      = "txt"
 	image    = "img"
@@ -25,20 +42,26 @@ const (
 
 type Message struct {
 	err        error
-	target     Target      // 消息目标
-	sender     string      // 发送方username
-	receivers  []string    // 接收方，接收方则为username
-	msgType    string      // 消息类型
-	msgBody    interface{} // 消息内容
-	syncDevice bool        // 消息发送成功后，是否将消息同步到发送方。
-	onlyOnline bool        // 只有接收方在线时，消息才能成功发送
-	ext        interface{} // 消息扩展字段
+	target     Target       // 消息目标
+	sender     string       // 发送方username
+	receivers  []string     // 接收方，接收方则为username
+	msgType    string       // 消息类型
+	msgBody    Body         // 消息内容
+	syncDevice bool         // 消息发送成功后，是否将消息同步到发送方。
+	onlyOnline bool         // 只有接收方在线时，消息才能成功发送
+	ext        interface{}  // 消息扩展字段
+	push       *PushOptions // 离线推送设置
 }
 
 func NewMessage(target Target) *Message {
 	return &Message{target: target}
 }
 
+// GetTarget 获取消息目标
+func (m *Message) GetTarget() Target {
+	return m.target
+}
+
 // AddReceivers 添加接收方
 func (m *Message) AddReceivers(receivers ...string) {
 	m.receivers = append(m.receivers, receivers...)
@@ -71,33 +94,19 @@ func (m *Message) GetType() string {
 }
 
 // GetBody 获取消息体
-func (m *Message) GetBody() interface{} {
+func (m *Message) GetBody() Body {
 	return m.msgBody
 }
 
 // SetBody 设置消息体
-func (m *Message) SetBody(body interface{}) {
-	switch body.(type) {
-	case MsgTxt, *MsgTxt:
-		m.msgType = txt
-		m.msgBody = body
-	case MsgImage, *MsgImage:
-		m.msgType = image
-	case MsgAudio, *MsgAudio:
-		m.msgType = audio
-	case MsgVideo, *MsgVideo:
-		m.msgType = video
-	case MsgFile, *MsgFile:
-		m.msgType = file
-	case MsgLocation, *MsgLocation:
-		m.msgType = location
-	case MsgCMD, *MsgCMD:
-		m.msgType = cmd
-	case MsgCustom, *MsgCustom:
-		m.msgType = custom
-	default:
-		m.err = errors.New("invalid msg")
+func (m *Message) SetBody(body Body) {
+	if isNil(body) {
+		m.err = errors.New("invalid msg: body is nil")
+		return
 	}
+
+	m.err = nil
+	m.msgType = body.msgType()
 	m.msgBody = body
 }
 
@@ -130,3 +139,26 @@ func (m *Message) SetExt(ext interface{}) {
 func (m *Message) GetExt() interface{} {
 	return m.ext
 }
+
+// SetPushOptions 设置离线推送，发送时合并至消息扩展字段
+func (m *Message) SetPushOptions(opts *PushOptions) {
+	m.push = opts
+}
+
+// GetPushOptions 获取离线推送设置
+func (m *Message) GetPushOptions() *PushOptions {
+	return m.push
+}
+
+// 构建发送使用的消息扩展字段
+func (m *Message) buildExt() (interface{}, error) {
+	if m.push == nil {
+		return m.ext, nil
+	}
+
+	if err := m.push.validate(); err != nil {
+		return nil, err
+	}
+
+	return m.push.mergeExt(m.ext)
+}
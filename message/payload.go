@@ -0,0 +1,150 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Payload 消息载荷
+// 环信在历史消息、回调等场景下返回的消息内容，消息体按类型解析为 MsgTxt、MsgImage 等消息体类型。
+type Payload struct {
+	From   string                 `json:"from"`   // 消息发送方。
+	To     string                 `json:"to"`     // 消息接收方。
+	Bodies []interface{}          `json:"bodies"` // 消息体，未知类型的消息体解析为 map[string]interface{}。
+	Ext    map[string]interface{} `json:"ext"`    // 消息扩展字段。
+}
+
+type payloadData struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Bodies []json.RawMessage `json:"bodies"`
+	Ext    json.RawMessage   `json:"ext"`
+}
+
+type bodyType struct {
+	Type string `json:"type"`
+}
+
+type msgImageData struct {
+	Filename string          `json:"filename"`
+	Secret   string          `json:"secret"`
+	Size     json.RawMessage `json:"size"`
+	Url      string          `json:"url"`
+}
+
+type msgImageSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type msgCustomData struct {
+	CustomEvent string          `json:"customEvent"`
+	CustomExts  json.RawMessage `json:"customExts"`
+	From        string          `json:"from"`
+	Ext         json.RawMessage `json:"ext"`
+}
+
+// UnmarshalJSON 解析消息载荷
+func (p *Payload) UnmarshalJSON(data []byte) error {
+	v := &payloadData{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	p.From, p.To = v.From, v.To
+	p.Bodies = make([]interface{}, 0, len(v.Bodies))
+	for _, raw := range v.Bodies {
+		t := &bodyType{}
+		if err := json.Unmarshal(raw, t); err != nil {
+			return err
+		}
+
+		body, err := decodeBody(t.Type, raw)
+		if err != nil {
+			return err
+		}
+		p.Bodies = append(p.Bodies, body)
+	}
+
+	p.Ext = nil
+	return unmarshalLoose(v.Ext, &p.Ext)
+}
+
+// DecodePayload 解析消息载荷
+// 兼容环信以JSON对象或JSON字符串返回的消息载荷，内容为空时返回 nil。
+func DecodePayload(data []byte) (*Payload, error) {
+	var payload *Payload
+	if err := unmarshalLoose(data, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// Message 将消息载荷转换为指定目标的消息，仅使用第一个消息体
+// 载荷中的发送方与接收方分别作为消息的发送方与唯一接收方，扩展字段作为消息扩展字段。
+func (p *Payload) Message(target Target) (*Message, error) {
+	if p == nil || len(p.Bodies) == 0 {
+		return nil, errors.New("the body of message is not set")
+	}
+
+	body, ok := p.Bodies[0].(Body)
+	if !ok {
+		return nil, fmt.Errorf("unsupported message body %T", p.Bodies[0])
+	}
+
+	msg := &Message{target: target, sender: p.From, msgType: body.msgType(), msgBody: body}
+	if p.To != "" {
+		msg.receivers = []string{p.To}
+	}
+	if p.Ext != nil {
+		msg.ext = p.Ext
+	}
+
+	return msg, nil
+}
+
+// EncodePayload 将消息编码为消息载荷
+// 消息体及扩展字段均为JSON对象，baseUrl 为文件地址前缀，格式为 https://{host}/{org_name}/{app_name}。
+func EncodePayload(msg *Message, baseUrl string) ([]byte, error) {
+	if msg.err != nil {
+		return nil, msg.err
+	}
+
+	if isNil(msg.msgBody) {
+		return nil, errors.New("the body of message is not set")
+	}
+
+	if err := msg.msgBody.validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := encodeBodyObject(msg.msgBody, baseUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := msg.buildExt()
+	if err != nil {
+		return nil, err
+	}
+
+	v := struct {
+		From   string                   `json:"from,omitempty"`
+		To     string                   `json:"to,omitempty"`
+		Bodies []map[string]interface{} `json:"bodies"`
+		Ext    interface{}              `json:"ext,omitempty"`
+	}{
+		From:   msg.sender,
+		Bodies: []map[string]interface{}{body},
+	}
+	if len(msg.receivers) > 0 {
+		v.To = msg.receivers[0]
+	}
+	if !isNil(ext) {
+		v.Ext = ext
+	}
+
+	return json.Marshal(v)
+}
@@ -0,0 +1,93 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	extApnsKey               = "em_apns_ext"
+	extPushTitleKey          = "em_push_title"
+	extPushContentKey        = "em_push_content"
+	extForceNotificationKey  = "em_force_notification"
+	extIgnoreNotificationKey = "em_ignore_notification"
+	extPushTemplateKey       = "em_push_template"
+)
+
+// PushOptions 离线推送设置
+// 发送消息时合并至消息扩展字段，用于控制接收方离线时的推送行为。
+type PushOptions struct {
+	Title              string                 // 推送标题，对应 em_apns_ext 中的 em_push_title。
+	Content            string                 // 推送内容，对应 em_apns_ext 中的 em_push_content。
+	ApnsExt            map[string]interface{} // em_apns_ext 中的其他推送设置，例如 em_push_sound、em_push_mutable_content。
+	ForceNotification  bool                   // 是否为强制推送消息，对应 em_force_notification，设置后忽略接收方的免打扰设置。
+	IgnoreNotification bool                   // 是否为静默消息，对应 em_ignore_notification，设置后不发送离线推送。
+	Template           *PushTemplate          // 推送模板，对应 em_push_template。
+}
+
+// PushTemplate 离线推送模板
+// 引用通过 push.API.CreateTemplate 创建的模板，参数依次替换模板标题及内容中的占位符。
+type PushTemplate struct {
+	Name        string   `json:"name"`                   // （必填）模板名称。
+	TitleArgs   []string `json:"title_args,omitempty"`   // （选填）模板标题参数。
+	ContentArgs []string `json:"content_args,omitempty"` // （选填）模板内容参数。
+}
+
+// 校验离线推送设置
+func (p *PushOptions) validate() error {
+	if p.Template != nil && p.Template.Name == "" {
+		return errors.New("invalid push options: template name is required")
+	}
+
+	if p.ForceNotification && p.IgnoreNotification {
+		return errors.New("invalid push options: force notification conflicts with ignore notification")
+	}
+
+	return nil
+}
+
+// 将离线推送设置合并至消息扩展字段，扩展字段需为JSON对象
+func (p *PushOptions) mergeExt(ext interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{})
+	if !isNil(ext) {
+		buf, err := json.Marshal(ext)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(buf, &merged); err != nil {
+			return nil, errors.New("invalid ext: ext should be a json object when push options is set")
+		}
+	}
+
+	apns, _ := merged[extApnsKey].(map[string]interface{})
+	if apns == nil {
+		apns = make(map[string]interface{})
+	}
+	for key, value := range p.ApnsExt {
+		apns[key] = value
+	}
+	if p.Title != "" {
+		apns[extPushTitleKey] = p.Title
+	}
+	if p.Content != "" {
+		apns[extPushContentKey] = p.Content
+	}
+	if len(apns) > 0 {
+		merged[extApnsKey] = apns
+	}
+
+	if p.ForceNotification {
+		merged[extForceNotificationKey] = true
+	}
+
+	if p.IgnoreNotification {
+		merged[extIgnoreNotificationKey] = true
+	}
+
+	if p.Template != nil {
+		merged[extPushTemplateKey] = p.Template
+	}
+
+	return merged, nil
+}
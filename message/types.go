@@ -1,5 +1,10 @@
 package message
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type MsgType string
 
 type sendReq struct {
@@ -10,12 +15,115 @@ type sendReq struct {
 	SyncDevice bool     `json:"sync_device,omitempty"`
 	RouteType  string   `json:"routetype,omitempty"`
 	Ext        string   `json:"ext,omitempty"`
+	IsThread   bool     `json:"is_thread,omitempty"`
 }
 
 type sendResp struct {
 	Data map[string]string `json:"data"`
 }
 
+type SendResult struct {
+	Receiver string `json:"receiver"` // 接收方，单聊为用户名，群聊为群组ID，聊天室为聊天室ID。
+	MsgID    string `json:"msg_id"`   // 消息ID。
+}
+
+type broadcastReq struct {
+	TargetType string                 `json:"target_type,omitempty"`
+	From       string                 `json:"from"`
+	Msg        map[string]interface{} `json:"msg"`
+	Ext        interface{}            `json:"ext,omitempty"`
+}
+
+type broadcastResp struct {
+	Data struct {
+		ID json.Number `json:"id"`
+	} `json:"data"`
+}
+
+type RoamingDirection string
+
+const (
+	RoamingDirectionUp   RoamingDirection = "up"   // 从结束时间向开始时间拉取，即由新到旧
+	RoamingDirectionDown RoamingDirection = "down" // 从开始时间向结束时间拉取，即由旧到新
+)
+
+type FetchRoamingMessagesArg struct {
+	Username  string           // （必填）拉取漫游消息的用户。
+	Peer      string           // （必填）会话对端，单聊为用户名，群聊为群组ID，聊天室为聊天室ID。
+	Target    Target           // （必填）会话类型，取值为 TargetUser、TargetGroup 或 TargetChatroom。
+	StartTime time.Time        // （选填）拉取的开始时间，为零值时不限制。
+	EndTime   time.Time        // （选填）拉取的结束时间，为零值时不限制。
+	Direction RoamingDirection // （选填）拉取方向，默认为 RoamingDirectionUp。
+	Limit     int              // （选填）每页拉取的消息数量，取值范围为 [1,50]，默认为 10。
+	Cursor    string           // （选填）开始获取数据的游标位置，首次请求不传，之后传入上一次请求返回的游标。
+}
+
+type FetchRoamingMessagesRet struct {
+	List    []*RoamingMessage `json:"list"`
+	HasMore bool              `json:"has_more"`
+	Cursor  string            `json:"cursor"`
+}
+
+type RoamingMessage struct {
+	MsgID     string   `json:"msg_id"`    // 消息ID。
+	Timestamp int64    `json:"timestamp"` // 消息发送时间，Unix 时间戳，单位为毫秒。
+	Message   *Message `json:"-"`         // 解析后的消息。
+}
+
+type fetchRoamingMessagesResp struct {
+	Data struct {
+		Messages []*struct {
+			MsgID     string   `json:"msg_id"`
+			Timestamp int64    `json:"timestamp"`
+			Payload   *Payload `json:"payload"`
+		} `json:"messages"`
+		Cursor string `json:"cursor"`
+	} `json:"data"`
+}
+
+type BatchSendResult struct {
+	Results map[string]*SendResult // 发送成功的接收方及发送结果。
+	Errors  map[string]error       // 发送失败的接收方及失败原因。
+}
+
+type RecallArg struct {
+	MsgID  string // （必填）要撤回的消息ID，即发送消息时返回的 msg_id。
+	Target Target // （必填）消息目标，取值为 TargetUser、TargetGroup、TargetChatroom 或 TargetThread。
+	To     string // （必填）消息的接收方，单聊为用户名，群聊为群组ID，聊天室为聊天室ID。
+	From   string // （选填）消息的发送方，默认为 admin。
+	Force  bool   // （选填）是否强制撤回，设置后忽略撤回时限，默认为 false。
+}
+
+type recallReq struct {
+	MsgID    string `json:"msg_id"`
+	To       string `json:"to"`
+	ChatType string `json:"chat_type"`
+	From     string `json:"from"`
+	Force    bool   `json:"force"`
+}
+
+type recallData struct {
+	MsgID        string `json:"msg_id"`
+	Recalled     string `json:"recalled"`
+	RecallFailed string `json:"recall_failed"`
+}
+
+type recallResp struct {
+	Data struct {
+		recallData
+		Msgs []*recallData `json:"msgs"`
+	} `json:"data"`
+}
+
+type RecallResult struct {
+	MsgID    string `json:"msg_id"`    // 消息ID。
+	To       string `json:"to"`        // 消息的接收方。
+	From     string `json:"from"`      // 消息的发送方。
+	ChatType string `json:"chat_type"` // 会话类型：- chat：单聊；- groupchat：群聊；- chatroom：聊天室。
+	Recalled bool   `json:"recalled"`  // 是否撤回成功。
+	Reason   string `json:"reason"`    // 撤回失败的原因，例如消息不存在或未强制撤回时已超过撤回时限。
+}
+
 type MsgTxt struct {
 	Msg string `json:"msg"` // 消息内容。
 }
@@ -98,7 +206,7 @@ type MsgCustom struct {
 
 type msgCustomBody struct {
 	CustomEvent string `json:"customEvent,omitempty"`
-	CustomExts  string `json:"CustomExts,omitempty"`
+	CustomExts  string `json:"customExts,omitempty"` // 键名与环信接口文档保持一致，为 customExts。
 	From        string `json:"from"`
 	Ext         string `json:"ext,omitempty"`
 }
@@ -0,0 +1,41 @@
+package im
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option 可选配置
+type Option func(o *options)
+
+type options struct {
+	scheme     string
+	httpClient *http.Client
+	proxy      *url.URL
+	timeout    time.Duration
+}
+
+// WithHTTPClient 设置HTTP客户端
+// 可用于配置双向TLS、代理及连接池等，设置后WithProxy不再生效。
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) { o.httpClient = client }
+}
+
+// WithScheme 设置请求协议
+// 取值为 http 或 https，默认为 https。可配合 http 协议访问本地模拟服务或私有化部署。
+func WithScheme(scheme string) Option {
+	return func(o *options) { o.scheme = scheme }
+}
+
+// WithProxy 设置HTTP代理
+// 仅对SDK内置的HTTP客户端生效。
+func WithProxy(proxy *url.URL) Option {
+	return func(o *options) { o.proxy = proxy }
+}
+
+// WithTimeout 设置单次请求的超时时间
+// 超时时间作用于每一次HTTP请求，重试时重新计时。
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
@@ -1,6 +1,7 @@
 package push
 
 import (
+	"context"
 	"fmt"
 	"github.com/dobyte/easemob-im-server-sdk/internal/core"
 )
@@ -12,6 +13,10 @@ const (
 )
 
 type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
 	// GetTemplate 查询离线推送模板
 	// 查询离线推送消息使用的模板。
 	// 点击查看详细文档:
@@ -39,6 +44,11 @@ func NewAPI(client core.Client) API {
 	return &api{client: client}
 }
 
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
 // GetTemplate 查询离线推送模板
 func (a *api) GetTemplate(name string) (*Template, error) {
 	resp := &getTemplateResp{}
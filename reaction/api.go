@@ -0,0 +1,127 @@
+package reaction
+
+import (
+	"context"
+	"fmt"
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"net/url"
+	"strconv"
+)
+
+const (
+	addReactionUri        = "/reaction/user/%s"
+	removeReactionUri     = "/reaction/user/%s?%s"
+	getReactionsUri       = "/reaction/user/%s?%s"
+	fetchReactionUsersUri = "/reaction/user/%s/detail?%s"
+)
+
+type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
+	// AddReaction 添加 Reaction
+	// 为指定用户在单聊或群聊消息上添加表情回复，reaction 为表情ID，与客户端一致。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/reaction#创建_添加_reaction
+	AddReaction(username, msgID, reaction string) (*Reaction, error)
+
+	// RemoveReaction 删除 Reaction
+	// 删除指定用户在消息上添加的表情回复。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/reaction#删除_reaction
+	RemoveReaction(username, msgID, reaction string) error
+
+	// GetReactions 根据消息ID获取 Reaction
+	// 批量获取消息的表情回复列表，每个表情最多返回 3 个添加该表情的用户。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/reaction#根据消息_id_获取_reaction
+	GetReactions(arg GetReactionsArg) ([]*MessageReactions, error)
+
+	// FetchReactionUsers 分页获取 Reaction 用户列表
+	// 根据消息ID及表情ID分页获取添加该表情的用户列表。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/reaction#根据消息_id_和表情_id_获取_reaction_信息
+	FetchReactionUsers(arg FetchReactionUsersArg) (*FetchReactionUsersRet, error)
+}
+
+type api struct {
+	client core.Client
+}
+
+func NewAPI(client core.Client) API {
+	return &api{client: client}
+}
+
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
+// AddReaction 添加 Reaction
+func (a *api) AddReaction(username, msgID, reaction string) (*Reaction, error) {
+	req := &addReactionReq{MsgID: msgID, Message: reaction}
+	resp := &addReactionResp{}
+	if err := a.client.Post(fmt.Sprintf(addReactionUri, username), req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// RemoveReaction 删除 Reaction
+func (a *api) RemoveReaction(username, msgID, reaction string) error {
+	query := new(url.URL).Query()
+	query.Set("msgId", msgID)
+	query.Set("message", reaction)
+
+	return a.client.Delete(fmt.Sprintf(removeReactionUri, username, query.Encode()), nil, nil)
+}
+
+// GetReactions 根据消息ID获取 Reaction
+func (a *api) GetReactions(arg GetReactionsArg) ([]*MessageReactions, error) {
+	msgType, err := arg.MsgType.ChatType()
+	if err != nil {
+		return nil, err
+	}
+
+	query := new(url.URL).Query()
+	for _, msgID := range arg.MsgIDs {
+		query.Add("msgIdList", msgID)
+	}
+	query.Set("msgType", msgType)
+	if arg.GroupID != "" {
+		query.Set("groupId", arg.GroupID)
+	}
+
+	resp := &getReactionsResp{}
+	if err := a.client.Get(fmt.Sprintf(getReactionsUri, arg.Username, query.Encode()), nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// FetchReactionUsers 分页获取 Reaction 用户列表
+func (a *api) FetchReactionUsers(arg FetchReactionUsersArg) (*FetchReactionUsersRet, error) {
+	query := new(url.URL).Query()
+	query.Set("msgId", arg.MsgID)
+	query.Set("message", arg.Reaction)
+	if arg.Limit > 0 {
+		query.Set("limit", strconv.Itoa(arg.Limit))
+	}
+	if arg.Cursor != "" {
+		query.Set("cursor", arg.Cursor)
+	}
+
+	resp := &fetchReactionUsersResp{}
+	if err := a.client.Get(fmt.Sprintf(fetchReactionUsersUri, arg.Username, query.Encode()), nil, resp); err != nil {
+		return nil, err
+	}
+
+	return &FetchReactionUsersRet{
+		List:    resp.Data.UserList,
+		HasMore: resp.Data.Cursor != "",
+		Cursor:  resp.Data.Cursor,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package reaction
+
+import (
+	"github.com/dobyte/easemob-im-server-sdk/internal/core"
+	"github.com/dobyte/easemob-im-server-sdk/message"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 创建请求指向测试服务的 Reaction 接口
+func newTestAPI(t *testing.T, handler http.HandlerFunc) API {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := core.NewClient(&core.Options{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+		AppKey: "org#app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewAPI(client)
+}
+
+func TestAPI_GetReactions(t *testing.T) {
+	var queries []url.Values
+
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/org/app/reaction/user/u1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		queries = append(queries, r.URL.Query())
+
+		w.Write([]byte(`{"data":[{"msgId":"m1","reactionList":[{"reactionId":"r1","reaction":"emoji_1","count":2,"state":true,"userList":["u1","u2"]}]}]}`))
+	})
+
+	rets, err := api.GetReactions(GetReactionsArg{Username: "u1", MsgIDs: []string{"m1", "m2"}, MsgType: message.TargetGroup, GroupID: "g1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*MessageReactions{{MsgID: "m1", ReactionList: []*ReactionDetail{{ReactionID: "r1", Reaction: "emoji_1", Count: 2, State: true, UserList: []string{"u1", "u2"}}}}}
+	if !reflect.DeepEqual(rets, want) {
+		t.Fatalf("unexpected reactions %+v", rets)
+	}
+
+	if _, err = api.GetReactions(GetReactionsArg{Username: "u1", MsgIDs: []string{"m3"}, MsgType: message.TargetUser}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = api.GetReactions(GetReactionsArg{Username: "u1", MsgIDs: []string{"m3"}, MsgType: message.Target(-1)}); err == nil {
+		t.Fatal("expected an error for an invalid message target")
+	}
+
+	wantQueries := []url.Values{
+		{"msgIdList": {"m1", "m2"}, "msgType": {"groupchat"}, "groupId": {"g1"}},
+		{"msgIdList": {"m3"}, "msgType": {"chat"}},
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Fatalf("unexpected queries %v, want %v", queries, wantQueries)
+	}
+}
@@ -0,0 +1,67 @@
+package reaction
+
+import "github.com/dobyte/easemob-im-server-sdk/message"
+
+type Reaction struct {
+	ID        string `json:"id"`        // Reaction ID。
+	MsgID     string `json:"msgId"`     // 消息ID。
+	MsgType   string `json:"msgType"`   // 消息的会话类型：- chat：单聊；- groupchat：群聊。
+	GroupID   string `json:"groupId"`   // 群组ID，单聊消息为空。
+	Reaction  string `json:"reaction"`  // 表情ID，与客户端一致。
+	CreatedAt string `json:"createdAt"` // 添加时间。
+	UpdatedAt string `json:"updatedAt"` // 修改时间。
+}
+
+type addReactionReq struct {
+	MsgID   string `json:"msgId"`
+	Message string `json:"message"`
+}
+
+type addReactionResp struct {
+	Data *Reaction `json:"data"`
+}
+
+type ReactionDetail struct {
+	ReactionID string   `json:"reactionId"` // Reaction ID。
+	Reaction   string   `json:"reaction"`   // 表情ID。
+	Count      int      `json:"count"`      // 添加该表情的用户数量。
+	State      bool     `json:"state"`      // 当前用户是否添加过该表情。
+	UserList   []string `json:"userList"`   // 添加该表情的用户列表，按添加时间升序，最多返回 3 个用户。
+}
+
+type MessageReactions struct {
+	MsgID        string            `json:"msgId"`        // 消息ID。
+	ReactionList []*ReactionDetail `json:"reactionList"` // 消息的表情列表。
+}
+
+type GetReactionsArg struct {
+	Username string         // （必填）当前用户的用户ID。
+	MsgIDs   []string       // （必填）消息ID列表，最多 20 个。
+	MsgType  message.Target // （必填）消息的会话类型，取值为 message.TargetUser 或 message.TargetGroup。
+	GroupID  string         // （选填）群组ID，会话类型为 groupchat 时必填。
+}
+
+type getReactionsResp struct {
+	Data []*MessageReactions `json:"data"`
+}
+
+type FetchReactionUsersArg struct {
+	Username string // （必填）当前用户的用户ID。
+	MsgID    string // （必填）消息ID。
+	Reaction string // （必填）表情ID。
+	Limit    int    // （选填）每页显示的用户数量，取值范围为 [1,100]，默认为 50。
+	Cursor   string // （选填）开始获取数据的游标位置，首次请求不传，之后传入上一次请求返回的游标。
+}
+
+type FetchReactionUsersRet struct {
+	List    []string `json:"list"`
+	HasMore bool     `json:"has_more"`
+	Cursor  string   `json:"cursor"`
+}
+
+type fetchReactionUsersResp struct {
+	Data struct {
+		ReactionDetail
+		Cursor string `json:"cursor"`
+	} `json:"data"`
+}
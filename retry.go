@@ -0,0 +1,13 @@
+package im
+
+import "github.com/dobyte/easemob-im-server-sdk/internal/core"
+
+// RetryPolicy 重试策略
+// 幂等请求在网络错误、429及5xx时重试；非幂等请求仅在服务端明确拒绝处理（429、503）时重试。
+type RetryPolicy = core.RetryPolicy
+
+// DefaultRetryPolicy 获取默认的重试策略
+// 最多尝试 3 次，首次重试等待 200ms，此后按 2 倍指数退避并加入 20% 的随机抖动，最长等待 5s。
+func DefaultRetryPolicy() *RetryPolicy {
+	return core.DefaultRetryPolicy()
+}
@@ -0,0 +1,21 @@
+package im
+
+import "github.com/dobyte/easemob-im-server-sdk/internal/core"
+
+// Token 访问令牌
+type Token = core.Token
+
+// TokenStore 令牌存储
+// 多个进程共享同一个令牌存储时，只需其中一个进程向环信申请令牌。
+type TokenStore = core.TokenStore
+
+// NewMemoryTokenStore 创建基于内存的令牌存储
+func NewMemoryTokenStore() TokenStore {
+	return core.NewMemoryTokenStore()
+}
+
+// NewFileTokenStore 创建基于文件的令牌存储
+// 令牌以JSON格式保存在指定文件中，写入时先写临时文件再原子替换，可供同一主机或共享存储上的多个进程使用。
+func NewFileTokenStore(path string) TokenStore {
+	return core.NewFileTokenStore(path)
+}
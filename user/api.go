@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"time"
 )
 
 const (
@@ -49,9 +51,14 @@ const (
 	getJoinedChatroomsUri                 = "/users/%s/joined_chatrooms"
 	getJoinedGroupUri                     = "/users/%s/joined_chatgroups"
 	fetchJoinedThreadsUri                 = "/threads/user/%s?limit=%d&cursor=%s&sort=%s"
+	deleteRoamingMessagesUri              = "/rest/message/roaming/user/%s/delete/all?%s"
 )
 
 type API interface {
+	// WithContext 绑定上下文
+	// 返回绑定了指定上下文的接口实例，通过该实例发起的所有请求都受上下文的取消和超时控制。
+	WithContext(ctx context.Context) API
+
 	// RegisterUsers 批量注册用户
 	// 批量注册是授权注册方式，服务端需要校验有效的 token 权限才能进行操作。
 	// 点击查看详细文档:
@@ -289,6 +296,13 @@ type API interface {
 	// 点击查看详细文档:
 	// https://docs-im.easemob.com/ccim/rest/group#获取一个用户加入的所有子区_分页获取
 	FetchJoinedThreads(arg FetchJoinedThreadsArg) (*FetchJoinedThreadsRet, error)
+
+	// DeleteRoamingMessages 单向清空用户的漫游消息
+	// 清空用户在指定时间及之前的全部会话的漫游消息，仅对该用户生效，会话对端仍可拉取这些消息。before 为零值时清空当前时间之前的漫游消息。
+	// 删除单个会话的漫游消息请使用 message.API.DeleteRoamingMessages。
+	// 点击查看详细文档:
+	// https://docs-im.easemob.com/ccim/rest/message#单向清空指定用户的漫游消息
+	DeleteRoamingMessages(username string, before time.Time) error
 }
 
 type api struct {
@@ -299,6 +313,11 @@ func NewAPI(client core.Client) API {
 	return &api{client: client}
 }
 
+// WithContext 绑定上下文
+func (a *api) WithContext(ctx context.Context) API {
+	return &api{client: a.client.WithContext(ctx)}
+}
+
 // RegisterUsers 注册用户
 func (a *api) RegisterUsers(users ...User) ([]*Entity, error) {
 	if len(users) > 60 {
@@ -740,3 +759,15 @@ func (a *api) FetchJoinedThreads(arg FetchJoinedThreadsArg) (*FetchJoinedThreads
 		Cursor:  resp.Properties.Cursor,
 	}, nil
 }
+
+// DeleteRoamingMessages 单向清空用户的漫游消息
+func (a *api) DeleteRoamingMessages(username string, before time.Time) error {
+	if before.IsZero() {
+		before = time.Now()
+	}
+
+	query := new(url.URL).Query()
+	query.Set("delTime", strconv.FormatInt(before.UnixNano()/int64(time.Millisecond), 10))
+
+	return a.client.Delete(fmt.Sprintf(deleteRoamingMessagesUri, username, query.Encode()), nil, nil)
+}